@@ -16,3 +16,10 @@ type Agent interface {
 	GetOutputKeys() []string
 	GetTools() []tools.Tool
 }
+
+// FinalAnswerGenerator is the interface of the agents that can be asked for
+// their best final answer given the previous steps, used by executors that
+// stop agents early with EarlyStoppingGenerate.
+type FinalAnswerGenerator interface {
+	GenerateFinalAnswer(ctx context.Context, intermediateSteps []schema.AgentStep, inputs map[string]string) (*schema.AgentFinish, error) //nolint:lll
+}
@@ -0,0 +1,126 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ApprovalDecision is the decision taken on a tool call that requires
+// approval.
+type ApprovalDecision int
+
+const (
+	// ApprovalRejected rejects the tool call, which is not executed.
+	ApprovalRejected ApprovalDecision = iota
+	// ApprovalApproved approves the tool call as is.
+	ApprovalApproved
+	// ApprovalEdited approves the tool call with an edited input.
+	ApprovalEdited
+)
+
+// Approval is the response of an Approver to a tool call.
+type Approval struct {
+	Decision ApprovalDecision
+	// Feedback is given to the agent as the observation of a rejected tool
+	// call. If empty, the agent is told that the call was rejected.
+	Feedback string
+	// ToolInput is the input an edited tool call is executed with.
+	ToolInput string
+}
+
+// Approve returns an approval of a tool call as is.
+func Approve() Approval {
+	return Approval{Decision: ApprovalApproved}
+}
+
+// ApproveWithInput returns an approval of a tool call with an edited input.
+func ApproveWithInput(toolInput string) Approval {
+	return Approval{Decision: ApprovalEdited, ToolInput: toolInput}
+}
+
+// Reject returns a rejection of a tool call with feedback for the agent.
+func Reject(feedback string) Approval {
+	return Approval{Decision: ApprovalRejected, Feedback: feedback}
+}
+
+// Approver decides whether the tool call of an agent action can be executed,
+// typically by asking a human. An error aborts the run of the executor.
+type Approver interface {
+	Approve(ctx context.Context, action schema.AgentAction) (Approval, error)
+}
+
+// ApproverFunc is an adapter to use a function as an Approver.
+type ApproverFunc func(ctx context.Context, action schema.AgentAction) (Approval, error)
+
+// Approve calls f(ctx, action).
+func (f ApproverFunc) Approve(ctx context.Context, action schema.AgentAction) (Approval, error) {
+	return f(ctx, action)
+}
+
+// ApprovalHandler is the struct used by the executor to get the tool calls of
+// the agent approved before executing them. If an executor has an
+// ApprovalHandler, the calls of the tools requiring approval are given to the
+// approver, which can approve them, edit their input or reject them. The
+// feedback of a rejection is added as the observation of the action, so the
+// agent can take it into account in its next step.
+type ApprovalHandler struct {
+	Approver Approver
+	// Tools are the names of the tools whose calls require approval. If empty,
+	// the calls of all the tools do.
+	Tools []string
+}
+
+// NewApprovalHandler creates a new approval handler requiring approval for
+// the calls of the given tools, or of all the tools if none is given.
+func NewApprovalHandler(approver Approver, tools ...string) *ApprovalHandler {
+	return &ApprovalHandler{
+		Approver: approver,
+		Tools:    tools,
+	}
+}
+
+// RequiresApproval reports whether the calls of a tool require approval. Tool
+// names are compared case-insensitively, like the executor does.
+func (h *ApprovalHandler) RequiresApproval(tool string) bool {
+	if len(h.Tools) == 0 {
+		return true
+	}
+	for _, t := range h.Tools {
+		if strings.EqualFold(t, tool) {
+			return true
+		}
+	}
+	return false
+}
+
+// approve returns the action to execute if the tool call of the action is
+// approved, or the observation to give to the agent if it is rejected.
+func (h *ApprovalHandler) approve(
+	ctx context.Context,
+	action schema.AgentAction,
+) (schema.AgentAction, string, bool, error) {
+	if !h.RequiresApproval(action.Tool) {
+		return action, "", true, nil
+	}
+
+	approval, err := h.Approver.Approve(ctx, action)
+	if err != nil {
+		return action, "", false, fmt.Errorf("approving call of %s: %w", action.Tool, err)
+	}
+
+	switch approval.Decision {
+	case ApprovalApproved:
+		return action, "", true, nil
+	case ApprovalEdited:
+		action.ToolInput = approval.ToolInput
+		return action, "", true, nil
+	case ApprovalRejected:
+	}
+	if approval.Feedback == "" {
+		return action, fmt.Sprintf("The call of %s was rejected by the user.", action.Tool), false, nil
+	}
+	return action, approval.Feedback, false, nil
+}
@@ -0,0 +1,111 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestExecutorWithApprovalHandler(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name            string
+		approval        agents.Approval
+		wantToolInputs  []string
+		wantStepInput   string
+		wantObservation string
+	}{
+		{
+			name:            "approved",
+			approval:        agents.Approve(),
+			wantToolInputs:  []string{"DROP TABLE users", "DROP TABLE users"},
+			wantStepInput:   "DROP TABLE users",
+			wantObservation: "done",
+		},
+		{
+			name:            "edited",
+			approval:        agents.ApproveWithInput("SELECT * FROM users"),
+			wantToolInputs:  []string{"SELECT * FROM users", "SELECT * FROM users"},
+			wantStepInput:   "SELECT * FROM users",
+			wantObservation: "done",
+		},
+		{
+			name:            "rejected",
+			approval:        agents.Reject("Do not delete data."),
+			wantStepInput:   "DROP TABLE users",
+			wantObservation: "Do not delete data.",
+		},
+		{
+			name:            "rejected without feedback",
+			approval:        agents.Approval{},
+			wantStepInput:   "DROP TABLE users",
+			wantObservation: "The call of sql was rejected by the user.",
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			tool := &testTool{name: "sql", output: "done"}
+			a := &testAgent{
+				actions: []schema.AgentAction{{Tool: "sql", ToolInput: "DROP TABLE users"}},
+				tools:   []tools.Tool{tool},
+			}
+			var approved []schema.AgentAction
+			approver := agents.ApproverFunc(func(_ context.Context, action schema.AgentAction) (agents.Approval, error) {
+				approved = append(approved, action)
+				return tc.approval, nil
+			})
+			executor := agents.NewExecutor(
+				a,
+				agents.WithMaxIterations(2),
+				agents.WithApprovalHandler(agents.NewApprovalHandler(approver, "SQL")),
+			)
+
+			_, err := chains.Call(context.Background(), executor, nil)
+			require.ErrorIs(t, err, agents.ErrNotFinished)
+			assert.Len(t, approved, 2)
+			assert.Equal(t, tc.wantToolInputs, tool.recordedInputs)
+			require.Len(t, a.recordedIntermediateSteps, 1)
+			assert.Equal(t, tc.wantStepInput, a.recordedIntermediateSteps[0].Action.ToolInput)
+			assert.Equal(t, tc.wantObservation, a.recordedIntermediateSteps[0].Observation)
+		})
+	}
+}
+
+func TestExecutorApprovalPolicy(t *testing.T) {
+	t.Parallel()
+
+	search := &testTool{name: "search", output: "result"}
+	a := &testAgent{
+		actions: []schema.AgentAction{{Tool: "search", ToolInput: "query"}},
+		tools:   []tools.Tool{search},
+	}
+	approver := agents.ApproverFunc(func(context.Context, schema.AgentAction) (agents.Approval, error) {
+		return agents.Approval{}, errors.New("unexpected approval")
+	})
+	executor := agents.NewExecutor(
+		a,
+		agents.WithMaxIterations(1),
+		agents.WithApprovalHandler(agents.NewApprovalHandler(approver, "sql")),
+	)
+
+	_, err := chains.Call(context.Background(), executor, nil)
+	require.ErrorIs(t, err, agents.ErrNotFinished)
+	assert.Equal(t, []string{"query"}, search.recordedInputs)
+
+	// Approval errors abort the run.
+	executor.ApprovalHandler.Tools = nil
+	_, err = chains.Call(context.Background(), executor, nil)
+	require.ErrorContains(t, err, "unexpected approval")
+	assert.Len(t, search.recordedInputs, 1)
+}
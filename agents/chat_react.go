@@ -0,0 +1,273 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/outputparser"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+const (
+	_defaultMaxParseRetries     = 2
+	_chatReActFinalAnswerAction = "final answer"
+)
+
+var (
+	// _chatReActMarkdown matches the bold markers and backticks models add
+	// around the labels of their actions.
+	_chatReActMarkdown    = regexp.MustCompile("\\*\\*|`")                                              //nolint:gochecknoglobals
+	_chatReActTextAction  = regexp.MustCompile(`(?is)\baction\s*:\s*(.*?)\s*\baction input\s*:\s*(.*)`) //nolint:gochecknoglobals,lll
+	_chatReActFinalAnswer = regexp.MustCompile(`(?is)\bfinal answer\s*:\s*(.*)`)                        //nolint:gochecknoglobals
+)
+
+// ChatReActAgent is an agent using the ReAct framework with a chat model. The
+// instructions and the descriptions of the tools are given in a system
+// message, followed by the input of the user and, for every intermediate step,
+// the output of the model as an AI message and the observation as a human
+// message.
+//
+// The agent expects actions as JSON blobs with "action" and "action_input"
+// keys, but also accepts them in markdown code blocks, in arrays, or in the
+// "Action:" and "Action Input:" text format. Outputs that cannot be parsed are
+// given back to the model with the parsing error, up to MaxParseRetries times.
+type ChatReActAgent struct {
+	// LLM is the chat model used by the agent.
+	LLM llms.Model
+	// Prompt is the template of the system message, formatted with the
+	// "today" variable.
+	Prompt prompts.PromptTemplate
+	// Tools is a list of the tools the agent can use.
+	Tools []tools.Tool
+	// Output key is the key where the final output is placed.
+	OutputKey string
+	// MaxParseRetries is the number of times the model is asked to fix an
+	// output that could not be parsed.
+	MaxParseRetries int
+	// CallbacksHandler is the handler for callbacks.
+	CallbacksHandler callbacks.Handler
+}
+
+var (
+	_ Agent                = (*ChatReActAgent)(nil)
+	_ FinalAnswerGenerator = (*ChatReActAgent)(nil)
+)
+
+// NewChatReActAgent creates a new ChatReActAgent with the given chat model,
+// tools and options.
+func NewChatReActAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *ChatReActAgent {
+	options := chatReActDefaultOptions()
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &ChatReActAgent{
+		LLM:              llm,
+		Prompt:           options.getChatReActPrompt(tools),
+		Tools:            tools,
+		OutputKey:        options.outputKey,
+		MaxParseRetries:  options.maxParseRetries,
+		CallbacksHandler: options.callbacksHandler,
+	}
+}
+
+// Plan decides what action to take or returns the final result of the input.
+func (a *ChatReActAgent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	messages, err := a.messages(intermediateSteps, inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		output, err := a.generate(ctx, messages)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		actions, finish, err := a.parseOutput(output)
+		if !errors.Is(err, ErrUnableToParseOutput) || attempt >= a.MaxParseRetries {
+			return actions, finish, err
+		}
+		messages = append(messages,
+			llms.TextParts(llms.ChatMessageTypeAI, output),
+			llms.TextParts(llms.ChatMessageTypeHuman, fmt.Sprintf(_chatReActParseErrorMessage, err)),
+		)
+	}
+}
+
+// GenerateFinalAnswer asks the agent for its best final answer given the
+// previous steps.
+func (a *ChatReActAgent) GenerateFinalAnswer(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) (*schema.AgentFinish, error) {
+	messages, err := a.messages(intermediateSteps, inputs)
+	if err != nil {
+		return nil, err
+	}
+	messages = append(messages, llms.TextParts(llms.ChatMessageTypeHuman,
+		`You must now give your final answer based on the previous steps, with the "Final Answer" action.`))
+
+	output, err := a.generate(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, finish, err := a.parseOutput(output); err == nil && finish != nil {
+		return finish, nil
+	}
+	return &schema.AgentFinish{
+		ReturnValues: map[string]any{a.OutputKey: strings.TrimSpace(output)},
+		Log:          output,
+	}, nil
+}
+
+func (a *ChatReActAgent) GetInputKeys() []string {
+	return []string{"input"}
+}
+
+func (a *ChatReActAgent) GetOutputKeys() []string {
+	return []string{a.OutputKey}
+}
+
+func (a *ChatReActAgent) GetTools() []tools.Tool {
+	return a.Tools
+}
+
+// messages returns the system message, the input and the scratchpad of the
+// intermediate steps.
+func (a *ChatReActAgent) messages(
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]llms.MessageContent, error) {
+	system, err := a.Prompt.Format(map[string]any{
+		"today": time.Now().Format("January 02, 2006"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	input := inputs["input"]
+	if history := inputs["history"]; history != "" {
+		input = fmt.Sprintf("Previous conversation history:\n%s\n\nNew input: %s", history, input)
+	}
+
+	messages := make([]llms.MessageContent, 0, 2+2*len(intermediateSteps))
+	messages = append(messages,
+		llms.TextParts(llms.ChatMessageTypeSystem, system),
+		llms.TextParts(llms.ChatMessageTypeHuman, input),
+	)
+	for _, step := range intermediateSteps {
+		if step.Action.Log != "" {
+			messages = append(messages, llms.TextParts(llms.ChatMessageTypeAI, step.Action.Log))
+		}
+		messages = append(messages, llms.TextParts(llms.ChatMessageTypeHuman, "Observation: "+step.Observation))
+	}
+
+	return messages, nil
+}
+
+func (a *ChatReActAgent) generate(ctx context.Context, messages []llms.MessageContent) (string, error) {
+	result, err := a.LLM.GenerateContent(ctx, messages,
+		llms.WithStopWords([]string{"\nObservation:"}),
+		llms.WithStreamingFunc(streamingFunc(ctx, a.CallbacksHandler)),
+	)
+	if err != nil {
+		return "", err
+	}
+	if len(result.Choices) == 0 {
+		return "", ErrAgentNoReturn
+	}
+
+	return result.Choices[0].Content, nil
+}
+
+func (a *ChatReActAgent) parseOutput(output string) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	tool, toolInput, ok := parseJSONAction(output)
+	if !ok {
+		tool, toolInput, ok = parseTextAction(output)
+	}
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: no action found in %q", ErrUnableToParseOutput, output)
+	}
+
+	if strings.EqualFold(strings.ReplaceAll(tool, "_", " "), _chatReActFinalAnswerAction) {
+		return nil, &schema.AgentFinish{
+			ReturnValues: map[string]any{a.OutputKey: toolInput},
+			Log:          output,
+		}, nil
+	}
+
+	return []schema.AgentAction{{Tool: tool, ToolInput: toolInput, Log: output}}, nil, nil
+}
+
+// chatReActAction is an action given as a JSON blob.
+type chatReActAction struct {
+	Action      string          `json:"action"`
+	ActionInput json.RawMessage `json:"action_input"`
+}
+
+// parseJSONAction returns the tool and input of the first action given as a
+// JSON blob in the output, if any. Inputs that are not strings are given to
+// the tool as JSON.
+func parseJSONAction(output string) (string, string, bool) {
+	jsonString, err := outputparser.ExtractJSON(output)
+	if err != nil {
+		return "", "", false
+	}
+
+	var action chatReActAction
+	if err := json.Unmarshal([]byte(jsonString), &action); err != nil {
+		var actions []chatReActAction
+		if err := json.Unmarshal([]byte(jsonString), &actions); err != nil || len(actions) == 0 {
+			return "", "", false
+		}
+		action = actions[0]
+	}
+	if action.Action == "" {
+		return "", "", false
+	}
+
+	var toolInput string
+	if err := json.Unmarshal(action.ActionInput, &toolInput); err != nil && len(action.ActionInput) > 0 {
+		toolInput = string(action.ActionInput)
+		if toolInput == "null" {
+			toolInput = ""
+		}
+	}
+	return strings.TrimSpace(action.Action), toolInput, true
+}
+
+// parseTextAction returns the tool and input of an action given in the
+// "Action:" and "Action Input:" text format, or of a "Final Answer:", ignoring
+// markdown formatting.
+func parseTextAction(output string) (string, string, bool) {
+	text := _chatReActMarkdown.ReplaceAllString(output, "")
+	action := _chatReActTextAction.FindStringSubmatchIndex(text)
+	final := _chatReActFinalAnswer.FindStringSubmatchIndex(text)
+
+	switch {
+	case final != nil && (action == nil || final[0] < action[0]):
+		return _chatReActFinalAnswerAction, strings.TrimSpace(text[final[2]:final[3]]), true
+	case action != nil:
+		tool := strings.Trim(strings.TrimSpace(text[action[2]:action[3]]), `"'`)
+		toolInput := strings.Trim(strings.TrimSpace(text[action[4]:action[5]]), `"'`)
+		return tool, toolInput, true
+	default:
+		return "", "", false
+	}
+}
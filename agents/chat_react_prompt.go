@@ -0,0 +1,65 @@
+package agents
+
+import (
+	"strings"
+
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/tools"
+)
+
+const (
+	_defaultChatReActPrefix = `Today is {{.today}}.
+Answer the following questions as best you can. You have access to the following tools:
+
+{{.tool_descriptions}}`
+
+	_defaultChatReActFormatInstructions = `Use a JSON blob to specify a tool, with an "action" key (the name of the tool) and an "action_input" key (the input of the tool).
+
+Valid "action" values are "Final Answer" or one of [ {{.tool_names}} ].
+
+Provide only ONE action per JSON blob, as shown:
+
+` + "```json" + `
+{
+  "action": $TOOL_NAME,
+  "action_input": $INPUT
+}
+` + "```" + `
+
+Use the following format:
+
+Thought: you should always think about what to do
+Action:
+` + "```json" + `
+$JSON_BLOB
+` + "```" + `
+Observation: the result of the action
+... (this Thought/Action/Observation can repeat N times)
+Thought: I now know the final answer
+Action:
+` + "```json" + `
+{
+  "action": "Final Answer",
+  "action_input": "the final answer to the original input question"
+}
+` + "```"
+
+	_defaultChatReActSuffix = `Begin! Always respond with a single action in a JSON blob. The observations of the actions are given to you in the following messages.`
+
+	_chatReActParseErrorMessage = `Your response could not be parsed: %s
+Respond again with a single action in a JSON blob, as described in the instructions.`
+)
+
+func createChatReActPrompt(tools []tools.Tool, prefix, instructions, suffix string) prompts.PromptTemplate {
+	template := strings.Join([]string{prefix, instructions, suffix}, "\n\n")
+
+	return prompts.PromptTemplate{
+		Template:       template,
+		TemplateFormat: prompts.TemplateFormatGoTemplate,
+		InputVariables: []string{"today"},
+		PartialVariables: map[string]any{
+			"tool_names":        toolNames(tools),
+			"tool_descriptions": toolDescriptions(tools),
+		},
+	}
+}
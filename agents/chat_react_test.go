@@ -0,0 +1,130 @@
+package agents_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+func TestChatReActAgent(t *testing.T) {
+	t.Parallel()
+
+	llm := &recordingLLM{LLM: fake.NewFakeLLM([]string{
+		"Thought: I should search.\nAction:\n```json\n{\n  \"action\": \"search\",\n  \"action_input\": \"answer\"\n}\n```",
+		"Thought: I now know the final answer\n```json\n{\"action\": \"Final Answer\", \"action_input\": \"42\"}\n```",
+	})}
+	tool := &testTool{name: "search", output: "The answer is 42."}
+	executor := agents.NewExecutor(agents.NewChatReActAgent(llm, []tools.Tool{tool}))
+
+	result, err := chains.Call(context.Background(), executor, map[string]any{"input": "What is the answer?"})
+	require.NoError(t, err)
+	assert.Equal(t, "42", result["output"])
+	assert.Equal(t, []string{"answer"}, tool.recordedInputs)
+
+	// The second call has the system message, the input, the output of the
+	// first call and its observation.
+	require.Len(t, llm.prompts, 2+4)
+	assert.Contains(t, llm.prompts[0], "search: ")
+	assert.Equal(t, "What is the answer?", llm.prompts[3])
+	assert.Contains(t, llm.prompts[4], `"action": "search"`)
+	assert.Equal(t, "Observation: The answer is 42.", llm.prompts[5])
+}
+
+func TestChatReActAgentParseOutput(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		output  string
+		actions []schema.AgentAction
+		finish  string
+	}{
+		{
+			name:    "json blob",
+			output:  `{"action": "search", "action_input": "answer"}`,
+			actions: []schema.AgentAction{{Tool: "search", ToolInput: "answer"}},
+		},
+		{
+			name:    "json array",
+			output:  "```\n[{\"action\": \"search\", \"action_input\": \"answer\"}]\n```",
+			actions: []schema.AgentAction{{Tool: "search", ToolInput: "answer"}},
+		},
+		{
+			name:    "json input",
+			output:  `{"action": "search", "action_input": {"query": "answer"}}`,
+			actions: []schema.AgentAction{{Tool: "search", ToolInput: `{"query": "answer"}`}},
+		},
+		{
+			name:    "markdown text",
+			output:  "**Action:** `search`\n**Action Input:** \"answer\"",
+			actions: []schema.AgentAction{{Tool: "search", ToolInput: "answer"}},
+		},
+		{
+			name:    "action in a word",
+			output:  "The last transaction: none found.\nAction: search\nAction Input: answer",
+			actions: []schema.AgentAction{{Tool: "search", ToolInput: "answer"}},
+		},
+		{
+			name:   "json final answer",
+			output: `{"action": "final_answer", "action_input": "42"}`,
+			finish: "42",
+		},
+		{
+			name:   "text final answer",
+			output: "I now know the final answer.\n**Final Answer:** 42",
+			finish: "42",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			agent := agents.NewChatReActAgent(fake.NewFakeLLM([]string{tc.output}), nil)
+			actions, finish, err := agent.Plan(context.Background(), nil, map[string]string{"input": "What is the answer?"})
+			require.NoError(t, err)
+			if tc.finish != "" {
+				require.NotNil(t, finish)
+				assert.Equal(t, tc.finish, finish.ReturnValues["output"])
+				return
+			}
+			require.Len(t, actions, len(tc.actions))
+			for i := range actions {
+				assert.Equal(t, tc.actions[i].Tool, actions[i].Tool)
+				assert.Equal(t, tc.actions[i].ToolInput, actions[i].ToolInput)
+				assert.Equal(t, tc.output, actions[i].Log)
+			}
+		})
+	}
+}
+
+func TestChatReActAgentParseRetries(t *testing.T) {
+	t.Parallel()
+
+	llm := &recordingLLM{LLM: fake.NewFakeLLM([]string{
+		"I am not sure what to do.",
+		`{"action": "Final Answer", "action_input": "42"}`,
+	})}
+	agent := agents.NewChatReActAgent(llm, nil)
+
+	_, finish, err := agent.Plan(context.Background(), nil, map[string]string{"input": "What is the answer?"})
+	require.NoError(t, err)
+	require.NotNil(t, finish)
+	assert.Equal(t, "42", finish.ReturnValues["output"])
+	require.Len(t, llm.prompts, 2+4)
+	assert.Equal(t, "I am not sure what to do.", llm.prompts[4])
+	assert.Contains(t, llm.prompts[5], "Your response could not be parsed")
+
+	agent = agents.NewChatReActAgent(fake.NewFakeLLM([]string{"I am not sure what to do."}), nil,
+		agents.WithMaxParseRetries(0))
+	_, _, err = agent.Plan(context.Background(), nil, map[string]string{"input": "What is the answer?"})
+	require.ErrorIs(t, err, agents.ErrUnableToParseOutput)
+}
@@ -0,0 +1,134 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Checkpoint is the state of a run of an executor, saved after every step so
+// the run can be resumed later, possibly by another process.
+type Checkpoint struct {
+	ID     string            `json:"id"`
+	Inputs map[string]string `json:"inputs"`
+	// Steps are the intermediate steps taken so far.
+	Steps []schema.AgentStep `json:"steps"`
+	// PendingActions are the actions planned by the agent in the current
+	// iteration that were not executed yet.
+	PendingActions []schema.AgentAction `json:"pending_actions,omitempty"`
+	// Iteration is the number of iterations completed.
+	Iteration int `json:"iteration"`
+	// Elapsed and TokensUsed are the time spent and the tokens used by the
+	// run so far, counted against the MaxDuration and MaxTokens budgets of
+	// the executor when the run is resumed.
+	Elapsed    time.Duration `json:"elapsed,omitempty"`
+	TokensUsed int           `json:"tokens_used,omitempty"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+
+	// start is the time the current call of the run started, and elapsed
+	// and tokensUsed the budget used by the run before it.
+	start      time.Time
+	elapsed    time.Duration
+	tokensUsed int
+}
+
+// CheckpointStore is the interface for storing the checkpoints of executor
+// runs.
+type CheckpointStore interface {
+	// Save saves a checkpoint, replacing the one with the same ID, if any.
+	Save(ctx context.Context, checkpoint Checkpoint) error
+	// Load returns the checkpoint with the given ID, or an error wrapping
+	// ErrCheckpointNotFound.
+	Load(ctx context.Context, id string) (Checkpoint, error)
+	// Delete deletes the checkpoint with the given ID, if any.
+	Delete(ctx context.Context, id string) error
+}
+
+type checkpointIDKey struct{}
+
+// ContextWithCheckpointID returns a copy of ctx with a checkpoint ID. An
+// executor with a checkpoint store called with this context saves the state
+// of its run under this ID after every step, or resumes the run saved under
+// it, with the inputs of the call replacing the saved ones. The checkpoint is
+// deleted when the agent finishes.
+func ContextWithCheckpointID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, checkpointIDKey{}, id)
+}
+
+// CheckpointIDFromContext returns the checkpoint ID of ctx, if any.
+func CheckpointIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(checkpointIDKey{}).(string)
+	return id, ok && id != ""
+}
+
+// FileCheckpointStore is a checkpoint store saving each checkpoint as a JSON
+// file in a directory.
+type FileCheckpointStore struct {
+	dir string
+}
+
+var _ CheckpointStore = &FileCheckpointStore{}
+
+// NewFileCheckpointStore creates a new file checkpoint store in dir, which is
+// created if needed.
+func NewFileCheckpointStore(dir string) (*FileCheckpointStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &FileCheckpointStore{dir: dir}, nil
+}
+
+// Save writes the checkpoint to a temporary file then renames it, so a
+// checkpoint is never partially written.
+func (s *FileCheckpointStore) Save(_ context.Context, checkpoint Checkpoint) error {
+	data, err := json.MarshalIndent(checkpoint, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(s.dir, ".checkpoint-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), s.path(checkpoint.ID))
+}
+
+func (s *FileCheckpointStore) Load(_ context.Context, id string) (Checkpoint, error) {
+	data, err := os.ReadFile(s.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return Checkpoint{}, fmt.Errorf("%w: %s", ErrCheckpointNotFound, id)
+	}
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	var checkpoint Checkpoint
+	err = json.Unmarshal(data, &checkpoint)
+	return checkpoint, err
+}
+
+func (s *FileCheckpointStore) Delete(_ context.Context, id string) error {
+	err := os.Remove(s.path(id))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *FileCheckpointStore) path(id string) string {
+	return filepath.Join(s.dir, url.PathEscape(id)+".json")
+}
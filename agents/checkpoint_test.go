@@ -0,0 +1,165 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// stepAgent calls its tool until it has taken the given number of steps, then
+// finishes with the last observation.
+type stepAgent struct {
+	tool  tools.Tool
+	steps int
+
+	numPlanCalls int
+	inputs       map[string]string
+}
+
+func (a *stepAgent) Plan(
+	_ context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	a.numPlanCalls++
+	a.inputs = inputs
+	if len(intermediateSteps) < a.steps {
+		return []schema.AgentAction{{Tool: a.tool.Name(), ToolInput: inputs["input"]}}, nil, nil
+	}
+	return nil, &schema.AgentFinish{
+		ReturnValues: map[string]any{"output": intermediateSteps[len(intermediateSteps)-1].Observation},
+	}, nil
+}
+
+func (a *stepAgent) GetInputKeys() []string {
+	return []string{"input"}
+}
+
+func (a *stepAgent) GetOutputKeys() []string {
+	return []string{"output"}
+}
+
+func (a *stepAgent) GetTools() []tools.Tool {
+	return []tools.Tool{a.tool}
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, err := agents.NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = store.Load(ctx, "runs/1")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+
+	checkpoint := agents.Checkpoint{
+		ID:             "runs/1",
+		Inputs:         map[string]string{"input": "hi"},
+		Steps:          []schema.AgentStep{{Observation: "seen"}},
+		PendingActions: []schema.AgentAction{{Tool: "search"}},
+		Iteration:      1,
+	}
+	require.NoError(t, store.Save(ctx, checkpoint))
+	loaded, err := store.Load(ctx, "runs/1")
+	require.NoError(t, err)
+	assert.Equal(t, checkpoint, loaded)
+
+	require.NoError(t, store.Delete(ctx, "runs/1"))
+	require.NoError(t, store.Delete(ctx, "runs/1"))
+	_, err = store.Load(ctx, "runs/1")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+}
+
+func TestExecutorResume(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, err := agents.NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+
+	tool := &testTool{name: "search", output: "found"}
+	a := &stepAgent{tool: tool, steps: 2}
+	errWaiting := errors.New("waiting for approval")
+	approvals := 0
+	approver := agents.ApproverFunc(func(context.Context, schema.AgentAction) (agents.Approval, error) {
+		approvals++
+		if approvals == 2 {
+			return agents.Approval{}, errWaiting
+		}
+		return agents.Approve(), nil
+	})
+	executor := agents.NewExecutor(a,
+		agents.WithCheckpointStore(store),
+		agents.WithApprovalHandler(agents.NewApprovalHandler(approver)),
+	)
+
+	// The run stops when the second tool call waits for approval.
+	_, err = chains.Call(agents.ContextWithCheckpointID(ctx, "run"), executor, map[string]any{"input": "query"})
+	require.ErrorIs(t, err, errWaiting)
+	checkpoint, err := store.Load(ctx, "run")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"input": "query"}, checkpoint.Inputs)
+	assert.Len(t, checkpoint.Steps, 1)
+	assert.Equal(t, []schema.AgentAction{{Tool: "search", ToolInput: "query"}}, checkpoint.PendingActions)
+	assert.Equal(t, 1, checkpoint.Iteration)
+
+	// The pending action is executed without planning again.
+	result, err := executor.Resume(ctx, "run")
+	require.NoError(t, err)
+	assert.Equal(t, "found", result["output"])
+	assert.Equal(t, 3, a.numPlanCalls)
+	assert.Equal(t, []string{"query", "query"}, tool.recordedInputs)
+	_, err = store.Load(ctx, "run")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+
+	_, err = executor.Resume(ctx, "run")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+	_, err = agents.NewExecutor(a).Resume(ctx, "run")
+	require.ErrorIs(t, err, agents.ErrNoCheckpointStore)
+}
+
+func TestExecutorResumeLoadsMemory(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, err := agents.NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+	mem := memory.NewConversationBuffer()
+	require.NoError(t, mem.ChatHistory.AddUserMessage(ctx, "My name is Ann."))
+
+	a := &stepAgent{tool: &testTool{name: "search", output: "found"}, steps: 1}
+	waiting := true
+	approver := agents.ApproverFunc(func(context.Context, schema.AgentAction) (agents.Approval, error) {
+		if waiting {
+			return agents.Approval{}, errors.New("waiting for approval")
+		}
+		return agents.Approve(), nil
+	})
+	executor := agents.NewExecutor(a,
+		agents.WithMemory(mem),
+		agents.WithCheckpointStore(store),
+		agents.WithApprovalHandler(agents.NewApprovalHandler(approver)),
+	)
+
+	_, err = chains.Call(agents.ContextWithCheckpointID(ctx, "run"), executor, map[string]any{"input": "query"})
+	require.Error(t, err)
+	assert.Equal(t, "Human: My name is Ann.", a.inputs["history"])
+
+	// The memory changed while the run was paused: the run resumes with the
+	// current memory, not the one saved in the checkpoint.
+	require.NoError(t, mem.ChatHistory.AddAIMessage(ctx, "Hi Ann!"))
+	waiting = false
+	_, err = executor.Resume(ctx, "run")
+	require.NoError(t, err)
+	assert.Equal(t, "Human: My name is Ann.\nAI: Hi Ann!", a.inputs["history"])
+	assert.Equal(t, "query", a.inputs["input"])
+}
@@ -38,7 +38,10 @@ type ConversationalAgent struct {
 	CallbacksHandler callbacks.Handler
 }
 
-var _ Agent = (*ConversationalAgent)(nil)
+var (
+	_ Agent                = (*ConversationalAgent)(nil)
+	_ FinalAnswerGenerator = (*ConversationalAgent)(nil)
+)
 
 func NewConversationalAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *ConversationalAgent {
 	options := conversationalDefaultOptions()
@@ -64,34 +67,44 @@ func (a *ConversationalAgent) Plan(
 	intermediateSteps []schema.AgentStep,
 	inputs map[string]string,
 ) ([]schema.AgentAction, *schema.AgentFinish, error) {
-	fullInputs := make(map[string]any, len(inputs))
-	for key, value := range inputs {
-		fullInputs[key] = value
+	output, err := a.predict(ctx, constructScratchPad(intermediateSteps), inputs)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	fullInputs["agent_scratchpad"] = constructScratchPad(intermediateSteps)
+	return a.parseOutput(output)
+}
 
-	var stream func(ctx context.Context, chunk []byte) error
+// GenerateFinalAnswer asks the agent for its best final answer given the
+// previous steps.
+func (a *ConversationalAgent) GenerateFinalAnswer(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) (*schema.AgentFinish, error) {
+	output, err := a.predict(ctx, constructScratchPad(intermediateSteps)+" "+_finalAnswerThought, inputs)
+	if err != nil {
+		return nil, err
+	}
 
-	if a.CallbacksHandler != nil {
-		stream = func(ctx context.Context, chunk []byte) error {
-			a.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
-			return nil
-		}
+	return textFinalAnswer(output, _conversationalFinalAnswerAction, a.OutputKey), nil
+}
+
+func (a *ConversationalAgent) predict(ctx context.Context, scratchPad string, inputs map[string]string) (string, error) {
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
 	}
 
-	output, err := chains.Predict(
+	fullInputs["agent_scratchpad"] = scratchPad
+
+	return chains.Predict(
 		ctx,
 		a.Chain,
 		fullInputs,
 		chains.WithStopWords([]string{"\nObservation:", "\n\tObservation:"}),
-		chains.WithStreamingFunc(stream),
+		chains.WithStreamingFunc(streamingFunc(ctx, a.CallbacksHandler)),
 	)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return a.parseOutput(output)
 }
 
 func (a *ConversationalAgent) GetInputKeys() []string {
@@ -10,7 +10,9 @@
 // Package agents provides and implementation of the agent interface called
 // OneShotZeroAgent. This agent uses the ReAct Framework (based on the
 // descriptions of tools) to decide what action to take. This agent is
-// optimized to be used with LLMs.
+// optimized to be used with LLMs. ChatReActAgent uses the same framework with
+// chat models, giving its steps as messages and asking for actions as JSON
+// blobs.
 //
 // To make agents more powerful we need to make them iterative, i.e. call the
 // model multiple times until they arrive at the final answer. That's the job of
@@ -19,4 +21,14 @@
 // calling the tool that the action references with the corresponding input,
 // getting the output of the tool, and then passing all that information back
 // into the Agent to get the next action it should take.
+//
+// An Executor with a CheckpointStore saves the state of the runs whose context
+// has a checkpoint ID after every step, so a run that was interrupted, for
+// instance while a tool call waits for approval, can be resumed later with
+// Executor.Resume.
+//
+// Executor.Stream runs an executor and returns a channel of typed events, such
+// as the tokens streamed by the LLM, the tool calls and their results, and the
+// final answer, to drive user interfaces without parsing the output of the
+// LLM.
 package agents
@@ -0,0 +1,95 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _finalAnswerThought is added to the scratchpad of text agents to ask them
+// for their final answer.
+const _finalAnswerThought = "I now need to return a final answer based on the previous steps:"
+
+// EarlyStoppingMethod is what the executor does when the agent has not
+// finished within the iterations, time or tokens it was given.
+type EarlyStoppingMethod string
+
+const (
+	// EarlyStoppingForce stops the run with an error wrapping ErrNotFinished.
+	EarlyStoppingForce EarlyStoppingMethod = "force"
+	// EarlyStoppingGenerate asks the agent for its best final answer given the
+	// steps taken, if it implements FinalAnswerGenerator, and stops the run
+	// with an error wrapping ErrNotFinished otherwise.
+	EarlyStoppingGenerate EarlyStoppingMethod = "generate"
+)
+
+// budgetUsed returns the time spent and the tokens used by the run of the
+// checkpoint, including those of the calls it was resumed from.
+func (e *Executor) budgetUsed(ctx context.Context, checkpoint *Checkpoint) (time.Duration, int) {
+	elapsed := checkpoint.elapsed + time.Since(checkpoint.start)
+	tokensUsed := checkpoint.tokensUsed
+	if e.TokenUsage != nil {
+		if usage, ok := e.TokenUsage.Spent(ctx); ok {
+			tokensUsed += usage.TotalTokens()
+		}
+	}
+	return elapsed, tokensUsed
+}
+
+// budgetExhausted returns an error wrapping ErrNotFinished if the time or
+// tokens budget of the run of the checkpoint is exhausted.
+func (e *Executor) budgetExhausted(ctx context.Context, checkpoint *Checkpoint) error {
+	elapsed, tokensUsed := e.budgetUsed(ctx, checkpoint)
+	if e.MaxDuration > 0 && elapsed >= e.MaxDuration {
+		return fmt.Errorf("%w: time budget of %s exhausted", ErrNotFinished, e.MaxDuration)
+	}
+	if e.MaxTokens > 0 && e.TokenUsage != nil && tokensUsed >= e.MaxTokens {
+		return fmt.Errorf("%w: budget of %d tokens exhausted", ErrNotFinished, e.MaxTokens)
+	}
+	return nil
+}
+
+// stopEarly ends a run that was not finished, generating a final answer if
+// the early stopping method of the executor is EarlyStoppingGenerate.
+func (e *Executor) stopEarly(ctx context.Context, checkpoint *Checkpoint, notFinished error) (map[string]any, error) {
+	if generator, ok := e.Agent.(FinalAnswerGenerator); ok && e.EarlyStoppingMethod == EarlyStoppingGenerate {
+		finish, err := generator.GenerateFinalAnswer(ctx, checkpoint.Steps, checkpoint.Inputs)
+		if err != nil {
+			return nil, err
+		}
+		if e.CallbacksHandler != nil {
+			e.CallbacksHandler.HandleAgentFinish(ctx, *finish)
+		}
+		if err := e.deleteCheckpoint(ctx, checkpoint); err != nil {
+			return nil, err
+		}
+		return e.getReturn(finish, checkpoint.Steps), nil
+	}
+
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleAgentFinish(ctx, schema.AgentFinish{
+			ReturnValues: map[string]any{"output": notFinished.Error()},
+		})
+	}
+	return e.getReturn(
+		&schema.AgentFinish{ReturnValues: make(map[string]any)},
+		checkpoint.Steps,
+	), notFinished
+}
+
+// textFinalAnswer returns the finish of a text agent asked for its final
+// answer: the text after the last final answer marker of its output, or the
+// whole output if there is none.
+func textFinalAnswer(output, finalAnswerAction, outputKey string) *schema.AgentFinish {
+	answer := output
+	if i := strings.LastIndex(output, finalAnswerAction); i >= 0 {
+		answer = output[i+len(finalAnswerAction):]
+	}
+	return &schema.AgentFinish{
+		ReturnValues: map[string]any{outputKey: strings.TrimSpace(answer)},
+		Log:          output,
+	}
+}
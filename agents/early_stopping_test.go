@@ -0,0 +1,151 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// recordingLLM is a fake LLM recording the text of its prompts.
+type recordingLLM struct {
+	*fake.LLM
+
+	prompts []string
+}
+
+func (l *recordingLLM) GenerateContent(
+	ctx context.Context,
+	messages []llms.MessageContent,
+	options ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	for _, m := range messages {
+		for _, part := range m.Parts {
+			if text, ok := part.(llms.TextContent); ok {
+				l.prompts = append(l.prompts, text.Text)
+			}
+		}
+	}
+	return l.LLM.GenerateContent(ctx, messages, options...)
+}
+
+// usageAgent is a test agent reporting the usage of 100 tokens for every
+// plan.
+type usageAgent struct {
+	*testAgent
+
+	usage *callbacks.CostHandler
+}
+
+func (a usageAgent) Plan(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	a.usage.HandleLLMGenerateContentEnd(ctx, &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		GenerationInfo: map[string]any{"PromptTokens": 90, "CompletionTokens": 10},
+	}}})
+	return a.testAgent.Plan(ctx, intermediateSteps, inputs)
+}
+
+func TestExecutorEarlyStoppingGenerate(t *testing.T) {
+	t.Parallel()
+
+	llm := &recordingLLM{LLM: fake.NewFakeLLM([]string{
+		"I should search.\nAction: search\nAction Input: answer",
+		"I now know the final answer\nFinal Answer: 42",
+	})}
+	tool := &testTool{name: "search", output: "The answer is 42."}
+	executor := agents.NewExecutor(
+		agents.NewOneShotAgent(llm, []tools.Tool{tool}),
+		agents.WithMaxIterations(1),
+		agents.WithEarlyStoppingMethod(agents.EarlyStoppingGenerate),
+	)
+
+	result, err := chains.Call(context.Background(), executor, map[string]any{"input": "What is the answer?"})
+	require.NoError(t, err)
+	assert.Equal(t, "42", result["output"])
+	require.Len(t, llm.prompts, 2)
+	assert.Contains(t, llm.prompts[1], "Observation: The answer is 42.\nThought: I now need to return a final answer")
+}
+
+func TestExecutorEarlyStoppingForce(t *testing.T) {
+	t.Parallel()
+
+	a := &testAgent{actions: []schema.AgentAction{{Tool: "search"}}}
+	executor := agents.NewExecutor(a,
+		agents.WithMaxDuration(time.Nanosecond),
+		// The test agent cannot generate a final answer.
+		agents.WithEarlyStoppingMethod(agents.EarlyStoppingGenerate),
+	)
+
+	_, err := chains.Call(context.Background(), executor, nil)
+	require.ErrorIs(t, err, agents.ErrNotFinished)
+	require.ErrorContains(t, err, "time budget of 1ns exhausted")
+	assert.Equal(t, 0, a.numPlanCalls)
+}
+
+func TestExecutorMaxTokens(t *testing.T) {
+	t.Parallel()
+
+	usage := callbacks.NewCostHandler()
+	a := usageAgent{testAgent: &testAgent{actions: []schema.AgentAction{{Tool: "search"}}}, usage: usage}
+	executor := agents.NewExecutor(a, agents.WithMaxTokens(150, usage))
+
+	_, err := chains.Call(context.Background(), executor, nil)
+	require.ErrorIs(t, err, agents.ErrNotFinished)
+	require.ErrorContains(t, err, "budget of 150 tokens exhausted")
+	assert.Equal(t, 2, a.numPlanCalls)
+	assert.Equal(t, 200, usage.Total().TotalTokens())
+}
+
+func TestExecutorResumeKeepsBudget(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, err := agents.NewFileCheckpointStore(t.TempDir())
+	require.NoError(t, err)
+	usage := callbacks.NewCostHandler()
+	tool := &testTool{name: "search", output: "found"}
+	a := usageAgent{
+		testAgent: &testAgent{actions: []schema.AgentAction{{Tool: "search"}}, tools: []tools.Tool{tool}},
+		usage:     usage,
+	}
+	waiting := true
+	approver := agents.ApproverFunc(func(context.Context, schema.AgentAction) (agents.Approval, error) {
+		if waiting {
+			return agents.Approval{}, errors.New("waiting for approval")
+		}
+		return agents.Approve(), nil
+	})
+	executor := agents.NewExecutor(a,
+		agents.WithMaxTokens(150, usage),
+		agents.WithCheckpointStore(store),
+		agents.WithApprovalHandler(agents.NewApprovalHandler(approver)),
+	)
+
+	_, err = chains.Call(agents.ContextWithCheckpointID(ctx, "run"), executor, nil)
+	require.Error(t, err)
+	checkpoint, err := store.Load(ctx, "run")
+	require.NoError(t, err)
+	assert.Equal(t, 100, checkpoint.TokensUsed)
+	assert.Positive(t, checkpoint.Elapsed)
+
+	// The tokens used before the pause count against the budget of the
+	// resumed run, which stops after planning once more.
+	waiting = false
+	_, err = executor.Resume(ctx, "run")
+	require.ErrorIs(t, err, agents.ErrNotFinished)
+	require.ErrorContains(t, err, "budget of 150 tokens exhausted")
+	assert.Equal(t, 2, a.numPlanCalls)
+}
@@ -10,11 +10,21 @@ var (
 	// ErrNotFinished is returned if the agent does not give a finish before  the number of iterations
 	// is larger than max iterations.
 	ErrNotFinished = errors.New("agent not finished before max iterations")
+	// ErrToolTimeout is returned if a tool call does not return within its timeout.
+	ErrToolTimeout = errors.New("tool call timed out")
+	// ErrRunTimeout is returned if the run of the executor does not finish within its timeout.
+	ErrRunTimeout = errors.New("agent run timed out")
 	// ErrUnknownAgentType is returned if the type given to the initializer is invalid.
 	ErrUnknownAgentType = errors.New("unknown agent type")
 	// ErrInvalidOptions is returned if the options given to the initializer is invalid.
 	ErrInvalidOptions = errors.New("invalid options")
 
+	// ErrCheckpointNotFound is returned if a checkpoint is not in the checkpoint store.
+	ErrCheckpointNotFound = errors.New("checkpoint not found")
+	// ErrNoCheckpointStore is returned if a run is resumed with an executor without a checkpoint
+	// store.
+	ErrNoCheckpointStore = errors.New("executor has no checkpoint store")
+
 	// ErrUnableToParseOutput is returned if the output of the llm is unparsable.
 	ErrUnableToParseOutput = errors.New("unable to parse agent output")
 	// ErrInvalidChainReturnType is returned if the internal chain of the agent returns a value in the
@@ -0,0 +1,105 @@
+package agents
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// EventType is the type of an event of a streamed executor run.
+type EventType string
+
+const (
+	// EventLLMToken is a chunk of text streamed by the LLM of the agent.
+	EventLLMToken EventType = "llm_token"
+	// EventToolStart is sent before a tool is called.
+	EventToolStart EventType = "tool_start"
+	// EventToolResult is sent with the observation of a tool call.
+	EventToolResult EventType = "tool_result"
+	// EventStep is sent when a step is finished, including the steps that do
+	// not call a tool, such as rejected tool calls or parsing errors.
+	EventStep EventType = "step"
+	// EventFinalAnswer is the last event of a successful run.
+	EventFinalAnswer EventType = "final_answer"
+	// EventError is the last event of a failed run.
+	EventError EventType = "error"
+)
+
+// Event is an event of a run of an executor streamed by Executor.Stream.
+type Event struct {
+	Type EventType `json:"type"`
+	// Chunk is the text of EventLLMToken events.
+	Chunk string `json:"chunk,omitempty"`
+	// Action is the action of EventToolStart, EventToolResult and EventStep
+	// events.
+	Action *schema.AgentAction `json:"action,omitempty"`
+	// Observation is the observation of EventToolResult and EventStep events.
+	Observation string `json:"observation,omitempty"`
+	// Outputs are the output values of EventFinalAnswer events.
+	Outputs map[string]any `json:"outputs,omitempty"`
+	// Err is the error of EventError events.
+	Err error `json:"-"`
+	// Error is the message of Err, set for EventError events so that it is
+	// included when the event is encoded as JSON.
+	Error string `json:"error,omitempty"`
+}
+
+type eventsKey struct{}
+
+// Stream runs the executor like chains.Call and returns a channel of the
+// events of the run, closed after the final answer or error. The channel must
+// be drained, or ctx canceled, for the run to make progress and its resources
+// to be released.
+func (e *Executor) Stream(ctx context.Context, inputValues map[string]any, options ...chains.ChainCallOption) <-chan Event { //nolint:lll
+	events := make(chan Event)
+	go func() {
+		defer close(events)
+		ctx := context.WithValue(ctx, eventsKey{}, (chan<- Event)(events))
+		outputs, err := chains.Call(ctx, e, inputValues, options...)
+		if err != nil {
+			sendEvent(ctx, Event{Type: EventError, Err: err, Error: err.Error()})
+			return
+		}
+		sendEvent(ctx, Event{Type: EventFinalAnswer, Outputs: outputs})
+	}()
+	return events
+}
+
+// sendEvent sends an event to the stream of ctx, if any, unless ctx is done.
+func sendEvent(ctx context.Context, event Event) {
+	events, ok := ctx.Value(eventsKey{}).(chan<- Event)
+	if !ok {
+		return
+	}
+	select {
+	case events <- event:
+	case <-ctx.Done():
+	}
+}
+
+// sendStepEvent sends an EventStep event for the last of the steps.
+func sendStepEvent(ctx context.Context, steps []schema.AgentStep) {
+	if len(steps) == 0 {
+		return
+	}
+	step := steps[len(steps)-1]
+	sendEvent(ctx, Event{Type: EventStep, Action: &step.Action, Observation: step.Observation})
+}
+
+// streamingFunc returns the streaming function of the LLM of an agent,
+// reporting the chunks to its callbacks handler and to the event stream of
+// ctx. It returns nil, disabling streaming, if there is neither.
+func streamingFunc(ctx context.Context, handler callbacks.Handler) func(ctx context.Context, chunk []byte) error {
+	if _, ok := ctx.Value(eventsKey{}).(chan<- Event); !ok && handler == nil {
+		return nil
+	}
+	return func(streamCtx context.Context, chunk []byte) error {
+		if handler != nil {
+			handler.HandleStreamingFunc(streamCtx, chunk)
+		}
+		sendEvent(ctx, Event{Type: EventLLMToken, Chunk: string(chunk)})
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package agents_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// streamingLLM is a fake LLM streaming its responses in a single chunk.
+type streamingLLM struct {
+	*fake.LLM
+}
+
+func (l streamingLLM) GenerateContent(
+	ctx context.Context,
+	messages []llms.MessageContent,
+	options ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	res, err := l.LLM.GenerateContent(ctx, messages, options...)
+	if err != nil {
+		return nil, err
+	}
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if opts.StreamingFunc != nil {
+		if err := opts.StreamingFunc(ctx, []byte(res.Choices[0].Content)); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func collectEvents(events <-chan agents.Event) []agents.Event {
+	var collected []agents.Event
+	for event := range events {
+		collected = append(collected, event)
+	}
+	return collected
+}
+
+func TestExecutorStream(t *testing.T) {
+	t.Parallel()
+
+	llm := streamingLLM{LLM: fake.NewFakeLLM([]string{
+		"Action: search\nAction Input: answer",
+		"Final Answer: 42",
+	})}
+	tool := &testTool{name: "search", output: "The answer is 42."}
+	executor := agents.NewExecutor(agents.NewOneShotAgent(llm, []tools.Tool{tool}))
+
+	events := collectEvents(executor.Stream(context.Background(), map[string]any{"input": "What is the answer?"}))
+	types := make([]agents.EventType, len(events))
+	for i, event := range events {
+		types[i] = event.Type
+	}
+	require.Equal(t, []agents.EventType{
+		agents.EventLLMToken,
+		agents.EventToolStart,
+		agents.EventToolResult,
+		agents.EventStep,
+		agents.EventLLMToken,
+		agents.EventFinalAnswer,
+	}, types)
+
+	assert.Equal(t, "Action: search\nAction Input: answer", events[0].Chunk)
+	assert.Equal(t, "search", events[1].Action.Tool)
+	assert.Equal(t, "answer", events[1].Action.ToolInput)
+	assert.Equal(t, "The answer is 42.", events[2].Observation)
+	assert.Equal(t, "The answer is 42.", events[3].Observation)
+	assert.Equal(t, " 42", events[5].Outputs["output"])
+}
+
+func TestExecutorStreamError(t *testing.T) {
+	t.Parallel()
+
+	errPlan := errors.New("plan failed")
+	a := &testAgent{
+		actions: []schema.AgentAction{{Tool: "unknown"}},
+	}
+	executor := agents.NewExecutor(a, agents.WithMaxIterations(1))
+
+	events := collectEvents(executor.Stream(context.Background(), nil))
+	require.Len(t, events, 2)
+	assert.Equal(t, agents.EventStep, events[0].Type)
+	assert.Equal(t, "unknown is not a valid tool, try another one", events[0].Observation)
+	assert.Equal(t, agents.EventError, events[1].Type)
+	require.ErrorIs(t, events[1].Err, agents.ErrNotFinished)
+	encoded, err := json.Marshal(events[1])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type": "error", "error": "agent not finished before max iterations"}`, string(encoded))
+
+	a.err = errPlan
+	events = collectEvents(executor.Stream(context.Background(), nil))
+	require.Len(t, events, 1)
+	require.ErrorIs(t, events[0].Err, errPlan)
+}
+
+func TestExecutorStreamCanceled(t *testing.T) {
+	t.Parallel()
+
+	a := &testAgent{actions: []schema.AgentAction{{Tool: "unknown"}}}
+	executor := agents.NewExecutor(a, agents.WithMaxIterations(100))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := executor.Stream(ctx, nil)
+	<-events
+	cancel()
+	// Events are dropped once ctx is canceled, so the run ends and the
+	// channel is closed.
+	for range events { //nolint:revive
+	}
+}
@@ -5,24 +5,53 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/chains"
 	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/tools"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
 )
 
 const _intermediateStepsOutputKey = "intermediateSteps"
 
-// Executor is the chain responsible for running agents.
+// Executor is the chain responsible for running agents. The tool calls of the
+// agent are reported to its callbacks handler, including their errors and
+// retries.
 type Executor struct {
 	Agent            Agent
 	Memory           schema.Memory
 	CallbacksHandler callbacks.Handler
 	ErrorHandler     *ParserErrorHandler
+	ApprovalHandler  *ApprovalHandler
+	CheckpointStore  CheckpointStore
+	ToolErrorHandler *ToolErrorHandler
 
 	MaxIterations           int
 	ReturnIntermediateSteps bool
+	// ToolTimeout is the maximum duration of a tool call, and ToolTimeouts
+	// overrides it for the tools with the given upper-cased names. Zero means
+	// no timeout.
+	ToolTimeout  time.Duration
+	ToolTimeouts map[string]time.Duration
+	// RunTimeout is the maximum duration of a run. Zero means no timeout.
+	RunTimeout time.Duration
+	// EarlyStoppingMethod is applied when the agent has not finished after
+	// MaxIterations iterations, or when the MaxDuration or MaxTokens budget
+	// is exhausted. Unlike RunTimeout, which cancels the run, budgets are
+	// checked before every iteration, and include the time and tokens used
+	// before the run was resumed.
+	EarlyStoppingMethod EarlyStoppingMethod
+	// MaxDuration is the duration after which no iteration is started. Zero
+	// means no limit.
+	MaxDuration time.Duration
+	// MaxTokens is the number of tokens after which no iteration is started,
+	// as counted by TokenUsage, which must be a callbacks handler of the LLM
+	// of the agent. Zero means no limit.
+	MaxTokens  int
+	TokenUsage *callbacks.CostHandler
 }
 
 var (
@@ -44,6 +73,16 @@ func NewExecutor(agent Agent, opts ...Option) *Executor {
 		ReturnIntermediateSteps: options.returnIntermediateSteps,
 		CallbacksHandler:        options.callbacksHandler,
 		ErrorHandler:            options.errorHandler,
+		ApprovalHandler:         options.approvalHandler,
+		CheckpointStore:         options.checkpointStore,
+		ToolErrorHandler:        options.toolErrorHandler,
+		ToolTimeout:             options.toolTimeout,
+		ToolTimeouts:            options.toolTimeouts,
+		RunTimeout:              options.runTimeout,
+		EarlyStoppingMethod:     options.earlyStoppingMethod,
+		MaxDuration:             options.maxDuration,
+		MaxTokens:               options.maxTokens,
+		TokenUsage:              options.tokenUsage,
 	}
 }
 
@@ -54,66 +93,173 @@ func (e *Executor) Call(ctx context.Context, inputValues map[string]any, _ ...ch
 	}
 	nameToTool := getNameToTool(e.Agent.GetTools())
 
-	steps := make([]schema.AgentStep, 0)
-	for i := 0; i < e.MaxIterations; i++ {
+	if e.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, e.RunTimeout, ErrRunTimeout)
+		defer cancel()
+	}
+
+	if e.TokenUsage != nil {
+		// A budget without limits tracks the tokens used by the run.
+		var cancel context.CancelFunc
+		ctx, cancel = e.TokenUsage.WithBudget(ctx, callbacks.Budget{})
+		defer cancel()
+	}
+
+	checkpoint, err := e.loadCheckpoint(ctx, inputs)
+	if err != nil {
+		return nil, err
+	}
+	for checkpoint.Iteration < e.MaxIterations {
+		if err := e.budgetExhausted(ctx, checkpoint); err != nil {
+			return e.stopEarly(ctx, checkpoint, err)
+		}
 		var finish map[string]any
-		steps, finish, err = e.doIteration(ctx, steps, nameToTool, inputs)
+		finish, err = e.doIteration(ctx, checkpoint, nameToTool)
+		if err != nil && errors.Is(context.Cause(ctx), ErrRunTimeout) {
+			return nil, fmt.Errorf("%w: %w", ErrRunTimeout, err)
+		}
 		if finish != nil || err != nil {
 			return finish, err
 		}
 	}
 
-	if e.CallbacksHandler != nil {
-		e.CallbacksHandler.HandleAgentFinish(ctx, schema.AgentFinish{
-			ReturnValues: map[string]any{"output": ErrNotFinished.Error()},
-		})
+	return e.stopEarly(ctx, checkpoint, ErrNotFinished)
+}
+
+// Resume resumes the run saved in the checkpoint store of the executor under
+// the given checkpoint ID, with the inputs it was started with.
+func (e *Executor) Resume(ctx context.Context, id string, options ...chains.ChainCallOption) (map[string]any, error) { //nolint:lll
+	if e.CheckpointStore == nil {
+		return nil, ErrNoCheckpointStore
 	}
-	return e.getReturn(
-		&schema.AgentFinish{ReturnValues: make(map[string]any)},
-		steps,
-	), ErrNotFinished
+	checkpoint, err := e.CheckpointStore.Load(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The inputs of the checkpoint include the memory variables loaded when
+	// the run was started: they are removed to be loaded again by chains.Call
+	// and replace the stale ones in loadCheckpoint.
+	memoryVariables := e.GetMemory().MemoryVariables(ctx)
+	inputs := make(map[string]any, len(checkpoint.Inputs))
+	for key, value := range checkpoint.Inputs {
+		if !slices.Contains(memoryVariables, key) {
+			inputs[key] = value
+		}
+	}
+	return chains.Call(ContextWithCheckpointID(ctx, id), e, inputs, options...)
 }
 
 func (e *Executor) doIteration( // nolint
 	ctx context.Context,
-	steps []schema.AgentStep,
+	checkpoint *Checkpoint,
 	nameToTool map[string]tools.Tool,
-	inputs map[string]string,
-) ([]schema.AgentStep, map[string]any, error) {
-	actions, finish, err := e.Agent.Plan(ctx, steps, inputs)
-	if errors.Is(err, ErrUnableToParseOutput) && e.ErrorHandler != nil {
-		formattedObservation := err.Error()
-		if e.ErrorHandler.Formatter != nil {
-			formattedObservation = e.ErrorHandler.Formatter(formattedObservation)
+) (map[string]any, error) {
+	if len(checkpoint.PendingActions) == 0 {
+		actions, finish, err := e.Agent.Plan(ctx, checkpoint.Steps, checkpoint.Inputs)
+		if errors.Is(err, ErrUnableToParseOutput) && e.ErrorHandler != nil {
+			formattedObservation := err.Error()
+			if e.ErrorHandler.Formatter != nil {
+				formattedObservation = e.ErrorHandler.Formatter(formattedObservation)
+			}
+			checkpoint.Steps = append(checkpoint.Steps, schema.AgentStep{
+				Observation: formattedObservation,
+			})
+			sendStepEvent(ctx, checkpoint.Steps)
+			checkpoint.Iteration++
+			return nil, e.saveCheckpoint(ctx, checkpoint)
+		}
+		if err != nil {
+			return nil, err
 		}
-		steps = append(steps, schema.AgentStep{
-			Observation: formattedObservation,
-		})
-		return steps, nil, nil
-	}
-	if err != nil {
-		return steps, nil, err
-	}
 
-	if len(actions) == 0 && finish == nil {
-		return steps, nil, ErrAgentNoReturn
-	}
+		if len(actions) == 0 && finish == nil {
+			return nil, ErrAgentNoReturn
+		}
 
-	if finish != nil {
-		if e.CallbacksHandler != nil {
-			e.CallbacksHandler.HandleAgentFinish(ctx, *finish)
+		if finish != nil {
+			if e.CallbacksHandler != nil {
+				e.CallbacksHandler.HandleAgentFinish(ctx, *finish)
+			}
+			if err := e.deleteCheckpoint(ctx, checkpoint); err != nil {
+				return nil, err
+			}
+			return e.getReturn(finish, checkpoint.Steps), nil
+		}
+
+		checkpoint.PendingActions = actions
+		if err := e.saveCheckpoint(ctx, checkpoint); err != nil {
+			return nil, err
 		}
-		return steps, e.getReturn(finish, steps), nil
 	}
 
-	for _, action := range actions {
-		steps, err = e.doAction(ctx, steps, nameToTool, action)
+	for len(checkpoint.PendingActions) > 0 {
+		steps, err := e.doAction(ctx, checkpoint.Steps, nameToTool, checkpoint.PendingActions[0])
 		if err != nil {
-			return steps, nil, err
+			return nil, err
+		}
+		checkpoint.Steps = steps
+		sendStepEvent(ctx, checkpoint.Steps)
+		checkpoint.PendingActions = checkpoint.PendingActions[1:]
+		if len(checkpoint.PendingActions) == 0 {
+			checkpoint.Iteration++
 		}
+		if err := e.saveCheckpoint(ctx, checkpoint); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// loadCheckpoint returns the checkpoint of the run to resume if the executor
+// has a checkpoint store and ctx a checkpoint ID, or a new checkpoint. The
+// inputs of the call, including the memory variables just loaded, replace
+// those of the checkpoint, and the budget it used is carried over.
+func (e *Executor) loadCheckpoint(ctx context.Context, inputs map[string]string) (*Checkpoint, error) {
+	id, ok := CheckpointIDFromContext(ctx)
+	if e.CheckpointStore == nil || !ok {
+		return &Checkpoint{Inputs: inputs, Steps: make([]schema.AgentStep, 0), start: time.Now()}, nil
+	}
+
+	checkpoint, err := e.CheckpointStore.Load(ctx, id)
+	if errors.Is(err, ErrCheckpointNotFound) {
+		return &Checkpoint{ID: id, Inputs: inputs, Steps: make([]schema.AgentStep, 0), start: time.Now()}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading checkpoint %s: %w", id, err)
+	}
+	if checkpoint.Inputs == nil {
+		checkpoint.Inputs = make(map[string]string, len(inputs))
+	}
+	maps.Copy(checkpoint.Inputs, inputs)
+	checkpoint.start = time.Now()
+	checkpoint.elapsed = checkpoint.Elapsed
+	checkpoint.tokensUsed = checkpoint.TokensUsed
+	return &checkpoint, nil
+}
+
+func (e *Executor) saveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	if e.CheckpointStore == nil || checkpoint.ID == "" {
+		return nil
+	}
+	checkpoint.Elapsed, checkpoint.TokensUsed = e.budgetUsed(ctx, checkpoint)
+	checkpoint.UpdatedAt = time.Now()
+	if err := e.CheckpointStore.Save(ctx, *checkpoint); err != nil {
+		return fmt.Errorf("saving checkpoint %s: %w", checkpoint.ID, err)
 	}
+	return nil
+}
 
-	return steps, nil, nil
+func (e *Executor) deleteCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	if e.CheckpointStore == nil || checkpoint.ID == "" {
+		return nil
+	}
+	if err := e.CheckpointStore.Delete(ctx, checkpoint.ID); err != nil {
+		return fmt.Errorf("deleting checkpoint %s: %w", checkpoint.ID, err)
+	}
+	return nil
 }
 
 func (e *Executor) doAction(
@@ -134,10 +280,30 @@ func (e *Executor) doAction(
 		}), nil
 	}
 
-	observation, err := tool.Call(ctx, action.ToolInput)
+	if e.ApprovalHandler != nil {
+		var (
+			observation string
+			approved    bool
+			err         error
+		)
+		action, observation, approved, err = e.ApprovalHandler.approve(ctx, action)
+		if err != nil {
+			return nil, err
+		}
+		if !approved {
+			return append(steps, schema.AgentStep{
+				Action:      action,
+				Observation: observation,
+			}), nil
+		}
+	}
+
+	sendEvent(ctx, Event{Type: EventToolStart, Action: &action})
+	observation, err := e.callTool(ctx, tool, action.ToolInput)
 	if err != nil {
 		return nil, err
 	}
+	sendEvent(ctx, Event{Type: EventToolResult, Action: &action, Observation: observation})
 
 	return append(steps, schema.AgentStep{
 		Action:      action,
@@ -22,6 +22,7 @@ type testAgent struct {
 	err        error
 	inputKeys  []string
 	outputKeys []string
+	tools      []tools.Tool
 
 	recordedIntermediateSteps []schema.AgentStep
 	recordedInputs            map[string]string
@@ -49,7 +50,28 @@ func (a testAgent) GetOutputKeys() []string {
 }
 
 func (a *testAgent) GetTools() []tools.Tool {
-	return nil
+	return a.tools
+}
+
+type testTool struct {
+	name   string
+	output string
+	err    error
+
+	recordedInputs []string
+}
+
+func (t *testTool) Name() string {
+	return t.name
+}
+
+func (t *testTool) Description() string {
+	return "A tool for tests."
+}
+
+func (t *testTool) Call(_ context.Context, input string) (string, error) {
+	t.recordedInputs = append(t.recordedInputs, input)
+	return t.output, t.err
 }
 
 func TestExecutorWithErrorHandler(t *testing.T) {
@@ -36,7 +36,10 @@ type OneShotZeroAgent struct {
 	CallbacksHandler callbacks.Handler
 }
 
-var _ Agent = (*OneShotZeroAgent)(nil)
+var (
+	_ Agent                = (*OneShotZeroAgent)(nil)
+	_ FinalAnswerGenerator = (*OneShotZeroAgent)(nil)
+)
 
 // NewOneShotAgent creates a new OneShotZeroAgent with the given LLM model, tools,
 // and options. It returns a pointer to the created agent. The opts parameter
@@ -65,35 +68,45 @@ func (a *OneShotZeroAgent) Plan(
 	intermediateSteps []schema.AgentStep,
 	inputs map[string]string,
 ) ([]schema.AgentAction, *schema.AgentFinish, error) {
+	output, err := a.predict(ctx, constructMrklScratchPad(intermediateSteps), inputs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return a.parseOutput(output)
+}
+
+// GenerateFinalAnswer asks the agent for its best final answer given the
+// previous steps.
+func (a *OneShotZeroAgent) GenerateFinalAnswer(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) (*schema.AgentFinish, error) {
+	output, err := a.predict(ctx, constructMrklScratchPad(intermediateSteps)+"Thought: "+_finalAnswerThought, inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	return textFinalAnswer(output, _finalAnswerAction, a.OutputKey), nil
+}
+
+func (a *OneShotZeroAgent) predict(ctx context.Context, scratchPad string, inputs map[string]string) (string, error) {
 	fullInputs := make(map[string]any, len(inputs))
 	for key, value := range inputs {
 		fullInputs[key] = value
 	}
 
-	fullInputs["agent_scratchpad"] = constructMrklScratchPad(intermediateSteps)
+	fullInputs["agent_scratchpad"] = scratchPad
 	fullInputs["today"] = time.Now().Format("January 02, 2006")
 
-	var stream func(ctx context.Context, chunk []byte) error
-
-	if a.CallbacksHandler != nil {
-		stream = func(ctx context.Context, chunk []byte) error {
-			a.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
-			return nil
-		}
-	}
-
-	output, err := chains.Predict(
+	return chains.Predict(
 		ctx,
 		a.Chain,
 		fullInputs,
 		chains.WithStopWords([]string{"\nObservation:", "\n\tObservation:"}),
-		chains.WithStreamingFunc(stream),
+		chains.WithStreamingFunc(streamingFunc(ctx, a.CallbacksHandler)),
 	)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return a.parseOutput(output)
 }
 
 func (a *OneShotZeroAgent) GetInputKeys() []string {
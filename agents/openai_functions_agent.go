@@ -30,7 +30,10 @@ type OpenAIFunctionsAgent struct {
 	CallbacksHandler callbacks.Handler
 }
 
-var _ Agent = (*OpenAIFunctionsAgent)(nil)
+var (
+	_ Agent                = (*OpenAIFunctionsAgent)(nil)
+	_ FinalAnswerGenerator = (*OpenAIFunctionsAgent)(nil)
+)
 
 // NewOpenAIFunctionsAgent creates a new OpenAIFunctionsAgent.
 func NewOpenAIFunctionsAgent(llm llms.Model, tools []tools.Tool, opts ...Option) *OpenAIFunctionsAgent {
@@ -72,24 +75,65 @@ func (o *OpenAIFunctionsAgent) Plan(
 	intermediateSteps []schema.AgentStep,
 	inputs map[string]string,
 ) ([]schema.AgentAction, *schema.AgentFinish, error) {
-	fullInputs := make(map[string]any, len(inputs))
-	for key, value := range inputs {
-		fullInputs[key] = value
+	mcList, err := o.messageContents(o.constructScratchPad(intermediateSteps), inputs)
+	if err != nil {
+		return nil, nil, err
 	}
-	fullInputs[agentScratchpad] = o.constructScratchPad(intermediateSteps)
 
-	var stream func(ctx context.Context, chunk []byte) error
+	result, err := o.LLM.GenerateContent(ctx, mcList,
+		llms.WithFunctions(o.functions()), llms.WithStreamingFunc(streamingFunc(ctx, o.CallbacksHandler)))
+	if err != nil {
+		return nil, nil, err
+	}
 
-	if o.CallbacksHandler != nil {
-		stream = func(ctx context.Context, chunk []byte) error {
-			o.CallbacksHandler.HandleStreamingFunc(ctx, chunk)
-			return nil
-		}
+	return o.ParseOutput(result)
+}
+
+// GenerateFinalAnswer asks the model for its best final answer given the
+// previous steps, without letting it call functions.
+func (o *OpenAIFunctionsAgent) GenerateFinalAnswer(
+	ctx context.Context,
+	intermediateSteps []schema.AgentStep,
+	inputs map[string]string,
+) (*schema.AgentFinish, error) {
+	scratchPad := append(o.constructScratchPad(intermediateSteps), llms.HumanChatMessage{
+		Content: "You must now give your final answer based on the previous steps, without calling any function.",
+	})
+	mcList, err := o.messageContents(scratchPad, inputs)
+	if err != nil {
+		return nil, err
 	}
 
+	result, err := o.LLM.GenerateContent(ctx, mcList, llms.WithStreamingFunc(streamingFunc(ctx, o.CallbacksHandler)))
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Choices) == 0 {
+		return nil, ErrAgentNoReturn
+	}
+
+	content := result.Choices[0].Content
+	return &schema.AgentFinish{
+		ReturnValues: map[string]any{o.OutputKey: content},
+		Log:          content,
+	}, nil
+}
+
+// messageContents formats the prompt of the agent with the inputs and the
+// scratchpad.
+func (o *OpenAIFunctionsAgent) messageContents(
+	scratchPad []llms.ChatMessage,
+	inputs map[string]string,
+) ([]llms.MessageContent, error) {
+	fullInputs := make(map[string]any, len(inputs))
+	for key, value := range inputs {
+		fullInputs[key] = value
+	}
+	fullInputs[agentScratchpad] = scratchPad
+
 	prompt, err := o.Prompt.FormatPrompt(fullInputs)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	mcList := make([]llms.MessageContent, len(prompt.Messages()))
@@ -129,13 +173,7 @@ func (o *OpenAIFunctionsAgent) Plan(
 		mcList[i] = mc
 	}
 
-	result, err := o.LLM.GenerateContent(ctx, mcList,
-		llms.WithFunctions(o.functions()), llms.WithStreamingFunc(stream))
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return o.ParseOutput(result)
+	return mcList, nil
 }
 
 func (o *OpenAIFunctionsAgent) GetInputKeys() []string {
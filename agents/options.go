@@ -1,6 +1,9 @@
 package agents
 
 import (
+	"strings"
+	"time"
+
 	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/memory"
 	"github.com/tmc/langchaingo/prompts"
@@ -13,7 +16,18 @@ type Options struct {
 	memory                  schema.Memory
 	callbacksHandler        callbacks.Handler
 	errorHandler            *ParserErrorHandler
+	approvalHandler         *ApprovalHandler
+	checkpointStore         CheckpointStore
+	toolErrorHandler        *ToolErrorHandler
+	toolTimeout             time.Duration
+	toolTimeouts            map[string]time.Duration
+	runTimeout              time.Duration
+	earlyStoppingMethod     EarlyStoppingMethod
+	maxDuration             time.Duration
+	maxTokens               int
+	tokenUsage              *callbacks.CostHandler
 	maxIterations           int
+	maxParseRetries         int
 	returnIntermediateSteps bool
 	outputKey               string
 	promptPrefix            string
@@ -31,9 +45,10 @@ type Option func(*Options)
 
 func executorDefaultOptions() Options {
 	return Options{
-		maxIterations: _defaultMaxIterations,
-		outputKey:     _defaultOutputKey,
-		memory:        memory.NewSimple(),
+		maxIterations:       _defaultMaxIterations,
+		outputKey:           _defaultOutputKey,
+		memory:              memory.NewSimple(),
+		earlyStoppingMethod: EarlyStoppingForce,
 	}
 }
 
@@ -55,6 +70,16 @@ func conversationalDefaultOptions() Options {
 	}
 }
 
+func chatReActDefaultOptions() Options {
+	return Options{
+		promptPrefix:       _defaultChatReActPrefix,
+		formatInstructions: _defaultChatReActFormatInstructions,
+		promptSuffix:       _defaultChatReActSuffix,
+		outputKey:          _defaultOutputKey,
+		maxParseRetries:    _defaultMaxParseRetries,
+	}
+}
+
 func openAIFunctionsDefaultOptions() Options {
 	return Options{
 		systemMessage: "You are a helpful AI assistant.",
@@ -88,6 +113,19 @@ func (co Options) getConversationalPrompt(tools []tools.Tool) prompts.PromptTemp
 	)
 }
 
+func (co Options) getChatReActPrompt(tools []tools.Tool) prompts.PromptTemplate {
+	if co.prompt.Template != "" {
+		return co.prompt
+	}
+
+	return createChatReActPrompt(
+		tools,
+		co.promptPrefix,
+		co.formatInstructions,
+		co.promptSuffix,
+	)
+}
+
 // WithMaxIterations is an option for setting the max number of iterations the executor
 // will complete.
 func WithMaxIterations(iterations int) Option {
@@ -96,6 +134,14 @@ func WithMaxIterations(iterations int) Option {
 	}
 }
 
+// WithMaxParseRetries is an option for setting the number of times the chat ReAct agent asks the
+// model to fix an output that could not be parsed, before returning the parsing error.
+func WithMaxParseRetries(retries int) Option {
+	return func(co *Options) {
+		co.maxParseRetries = retries
+	}
+}
+
 // WithOutputKey is an option for setting the output key of the agent.
 func WithOutputKey(outputKey string) Option {
 	return func(co *Options) {
@@ -161,6 +207,81 @@ func WithParserErrorHandler(errorHandler *ParserErrorHandler) Option {
 	}
 }
 
+// WithApprovalHandler is an option for setting an approval handler to an executor, to get the
+// tool calls of the agent approved before executing them.
+func WithApprovalHandler(approvalHandler *ApprovalHandler) Option {
+	return func(co *Options) {
+		co.approvalHandler = approvalHandler
+	}
+}
+
+// WithCheckpointStore is an option for setting a checkpoint store to an executor. Runs whose
+// context has a checkpoint ID are saved in the store after every step, and can be resumed.
+func WithCheckpointStore(store CheckpointStore) Option {
+	return func(co *Options) {
+		co.checkpointStore = store
+	}
+}
+
+// WithToolErrorHandler is an option for setting a tool error handler to an executor, to retry
+// failed tool calls or give their errors to the agent instead of aborting the run.
+func WithToolErrorHandler(toolErrorHandler *ToolErrorHandler) Option {
+	return func(co *Options) {
+		co.toolErrorHandler = toolErrorHandler
+	}
+}
+
+// WithToolTimeout is an option for setting the maximum duration of the calls of the given tools,
+// or of all the tools if none is given. Tool names are case insensitive, as when the executor
+// dispatches actions to tools.
+func WithToolTimeout(timeout time.Duration, tools ...string) Option {
+	return func(co *Options) {
+		if len(tools) == 0 {
+			co.toolTimeout = timeout
+			return
+		}
+		if co.toolTimeouts == nil {
+			co.toolTimeouts = make(map[string]time.Duration, len(tools))
+		}
+		for _, tool := range tools {
+			co.toolTimeouts[strings.ToUpper(tool)] = timeout
+		}
+	}
+}
+
+// WithRunTimeout is an option for setting the maximum duration of the runs of an executor.
+func WithRunTimeout(timeout time.Duration) Option {
+	return func(co *Options) {
+		co.runTimeout = timeout
+	}
+}
+
+// WithEarlyStoppingMethod is an option for setting what the executor does when the agent has not
+// finished within its iterations, time or tokens.
+func WithEarlyStoppingMethod(method EarlyStoppingMethod) Option {
+	return func(co *Options) {
+		co.earlyStoppingMethod = method
+	}
+}
+
+// WithMaxDuration is an option for setting the duration after which the executor starts no
+// iteration.
+func WithMaxDuration(maxDuration time.Duration) Option {
+	return func(co *Options) {
+		co.maxDuration = maxDuration
+	}
+}
+
+// WithMaxTokens is an option for setting the number of tokens after which the executor starts no
+// iteration. The tokens are counted by usage, which must be a callbacks handler of the LLM of the
+// agent.
+func WithMaxTokens(maxTokens int, usage *callbacks.CostHandler) Option {
+	return func(co *Options) {
+		co.maxTokens = maxTokens
+		co.tokenUsage = usage
+	}
+}
+
 type OpenAIOption struct{}
 
 func NewOpenAIOption() OpenAIOption {
@@ -0,0 +1,179 @@
+// Package sqlite3 adds support for
+// agent executor checkpoints using sqlite3.
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+	"github.com/tmc/langchaingo/agents"
+)
+
+// DefaultCheckpointTableName sets a default table name for the checkpoint store.
+const DefaultCheckpointTableName = "langchaingo_checkpoints"
+
+// DefaultCheckpointSchema sets a default schema for the checkpoint store to be run after connecting.
+const DefaultCheckpointSchema = `CREATE TABLE IF NOT EXISTS %s (
+		id TEXT PRIMARY KEY,
+		checkpoint TEXT NOT NULL,
+		updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);`
+
+// SqliteCheckpointStore is a checkpoint store for agent executors backed by sqlite3.
+type SqliteCheckpointStore struct {
+	// DB is the database connection.
+	DB *sql.DB
+	// Ctx is a context that can be used for the schema exec.
+	//nolint:containedctx // This is used only when execing schema.
+	Ctx context.Context
+	// DBAddress is the address or file path for connecting the db.
+	DBAddress string
+	// TableName is the name of the checkpoints table.
+	TableName string
+	// Schema defines a initial schema to be run.
+	Schema []byte
+}
+
+// Statically assert that SqliteCheckpointStore implement the checkpoint store interface.
+var _ agents.CheckpointStore = &SqliteCheckpointStore{}
+
+// NewSqliteCheckpointStore creates a new SqliteCheckpointStore using checkpoint store options.
+func NewSqliteCheckpointStore(options ...SqliteCheckpointStoreOption) *SqliteCheckpointStore {
+	return applyCheckpointStoreOptions(options...)
+}
+
+// Save stores a checkpoint, replacing the one with the same ID, if any.
+func (s *SqliteCheckpointStore) Save(ctx context.Context, checkpoint agents.Checkpoint) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	querytpl := []string{
+		"INSERT INTO ",
+		" (id, checkpoint) VALUES (?, ?)" +
+			" ON CONFLICT (id) DO UPDATE SET checkpoint = excluded.checkpoint, updated = CURRENT_TIMESTAMP;",
+	}
+	query := strings.Join(querytpl, s.TableName)
+	_, err = s.DB.ExecContext(ctx, query, checkpoint.ID, string(data))
+	return err
+}
+
+// Load returns the checkpoint with the given ID.
+func (s *SqliteCheckpointStore) Load(ctx context.Context, id string) (agents.Checkpoint, error) {
+	querytpl := []string{
+		"SELECT checkpoint FROM ",
+		" WHERE id = ?;",
+	}
+	query := strings.Join(querytpl, s.TableName)
+
+	var data string
+	err := s.DB.QueryRowContext(ctx, query, id).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return agents.Checkpoint{}, fmt.Errorf("%w: %s", agents.ErrCheckpointNotFound, id)
+	}
+	if err != nil {
+		return agents.Checkpoint{}, err
+	}
+
+	var checkpoint agents.Checkpoint
+	err = json.Unmarshal([]byte(data), &checkpoint)
+	return checkpoint, err
+}
+
+// Delete removes a checkpoint from the store.
+func (s *SqliteCheckpointStore) Delete(ctx context.Context, id string) error {
+	querytpl := []string{
+		"DELETE FROM ",
+		" WHERE id = ?;",
+	}
+	query := strings.Join(querytpl, s.TableName)
+	_, err := s.DB.ExecContext(ctx, query, id)
+	return err
+}
+
+// SqliteCheckpointStoreOption is a function for creating new
+// checkpoint store with other than the default values.
+type SqliteCheckpointStoreOption func(s *SqliteCheckpointStore)
+
+// WithDB is an option for NewSqliteCheckpointStore for adding
+// a database connection.
+func WithDB(db *sql.DB) SqliteCheckpointStoreOption {
+	return func(s *SqliteCheckpointStore) {
+		s.DB = db
+	}
+}
+
+// WithContext is an option for NewSqliteCheckpointStore
+// to use a context internally when running Schema.
+func WithContext(ctx context.Context) SqliteCheckpointStoreOption {
+	return func(s *SqliteCheckpointStore) {
+		s.Ctx = ctx //nolint:fatcontext
+	}
+}
+
+// WithDBAddress is an option for NewSqliteCheckpointStore for
+// specifying an address or file path for when connecting the db.
+func WithDBAddress(addr string) SqliteCheckpointStoreOption {
+	return func(s *SqliteCheckpointStore) {
+		s.DBAddress = addr
+	}
+}
+
+// WithTableName is an option for NewSqliteCheckpointStore for
+// specifying the name of the checkpoints table.
+func WithTableName(name string) SqliteCheckpointStoreOption {
+	return func(s *SqliteCheckpointStore) {
+		s.TableName = name
+	}
+}
+
+// WithSchema is an option for NewSqliteCheckpointStore for
+// running a schema when connected. Useful for migrations for example.
+func WithSchema(schema []byte) SqliteCheckpointStoreOption {
+	return func(s *SqliteCheckpointStore) {
+		s.Schema = schema
+	}
+}
+
+func applyCheckpointStoreOptions(options ...SqliteCheckpointStoreOption) *SqliteCheckpointStore {
+	s := &SqliteCheckpointStore{}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.TableName == "" {
+		s.TableName = DefaultCheckpointTableName
+	}
+
+	if s.Schema == nil {
+		s.Schema = []byte(fmt.Sprintf(DefaultCheckpointSchema, s.TableName))
+	}
+
+	if s.Ctx == nil {
+		s.Ctx = context.Background()
+	}
+
+	if s.DBAddress == "" {
+		s.DBAddress = ":memory:"
+	}
+
+	if s.DB == nil {
+		db, err := sql.Open("sqlite3", s.DBAddress)
+		if err != nil {
+			panic(err)
+		}
+		s.DB = db
+	}
+
+	if _, err := s.DB.ExecContext(s.Ctx, string(s.Schema)); err != nil {
+		panic(err)
+	}
+
+	return s
+}
@@ -0,0 +1,42 @@
+package sqlite3_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/agents/sqlite3"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestSqliteCheckpointStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := sqlite3.NewSqliteCheckpointStore(sqlite3.WithContext(ctx))
+
+	_, err := s.Load(ctx, "run")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+
+	checkpoint := agents.Checkpoint{
+		ID:        "run",
+		Inputs:    map[string]string{"input": "What is 2+2?"},
+		Steps:     []schema.AgentStep{{Action: schema.AgentAction{Tool: "calculator", ToolInput: "2+2"}, Observation: "4"}},
+		Iteration: 1,
+		UpdatedAt: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC),
+	}
+	require.NoError(t, s.Save(ctx, checkpoint))
+	checkpoint.Iteration = 2
+	require.NoError(t, s.Save(ctx, checkpoint))
+
+	loaded, err := s.Load(ctx, "run")
+	require.NoError(t, err)
+	assert.Equal(t, checkpoint, loaded)
+
+	require.NoError(t, s.Delete(ctx, "run"))
+	_, err = s.Load(ctx, "run")
+	require.ErrorIs(t, err, agents.ErrCheckpointNotFound)
+}
@@ -0,0 +1,144 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// ToolErrorPolicy is what the executor does when a tool call fails.
+type ToolErrorPolicy int
+
+const (
+	// ToolErrorAbort aborts the run with the error of the tool.
+	ToolErrorAbort ToolErrorPolicy = iota
+	// ToolErrorObserve gives the error to the agent as the observation of the
+	// action, so it can try something else.
+	ToolErrorObserve
+)
+
+// ToolErrorHandler is the struct used to handle the errors of tool calls in the executor. If an
+// executor has a ToolErrorHandler, failed tool calls are retried up to MaxRetries times, then the
+// policy is applied. Without one, the run is aborted on the first error.
+type ToolErrorHandler struct {
+	// Policy is applied when a tool call still fails after its retries.
+	Policy ToolErrorPolicy
+	// MaxRetries is the number of times a failed tool call is retried.
+	MaxRetries int
+	// The formatter function can be used to format the errors given as observations. If nil the
+	// observation is "Error: " followed by the error.
+	Formatter func(err error) string
+}
+
+// NewToolErrorHandler creates a new tool error handler.
+func NewToolErrorHandler(policy ToolErrorPolicy, maxRetries int) *ToolErrorHandler {
+	return &ToolErrorHandler{
+		Policy:     policy,
+		MaxRetries: maxRetries,
+	}
+}
+
+func (h *ToolErrorHandler) format(err error) string {
+	if h.Formatter != nil {
+		return h.Formatter(err)
+	}
+	return fmt.Sprintf("Error: %s", err)
+}
+
+// callTool calls a tool, retrying and applying the policy of the tool error
+// handler on errors. It returns the observation of the call, or the error
+// aborting the run.
+func (e *Executor) callTool(ctx context.Context, tool tools.Tool, input string) (string, error) {
+	maxRetries := 0
+	if e.ToolErrorHandler != nil {
+		maxRetries = e.ToolErrorHandler.MaxRetries
+	}
+
+	var err error
+	for attempt := 0; attempt <= maxRetries; attempt++ {
+		var observation string
+		observation, err = e.callToolOnce(ctx, tool, input)
+		if err == nil {
+			return observation, nil
+		}
+		// The run was canceled or timed out: retrying is useless.
+		if ctx.Err() != nil {
+			return "", err
+		}
+	}
+
+	if e.ToolErrorHandler == nil || e.ToolErrorHandler.Policy == ToolErrorAbort {
+		return "", err
+	}
+	return e.ToolErrorHandler.format(err), nil
+}
+
+// callToolOnce calls a tool within its deadline, if any, reporting the call to
+// the callbacks handler of the executor. The run of the tool is only started
+// in ctx when the executor reports the call, so that tools reporting their own
+// calls to the same callbacks handler do not report them twice.
+func (e *Executor) callToolOnce(ctx context.Context, tool tools.Tool, input string) (string, error) {
+	if e.CallbacksHandler != nil {
+		ctx = callbacks.StartToolRun(ctx, tool.Name(), e.CallbacksHandler)
+		e.CallbacksHandler.HandleToolStart(ctx, input)
+	}
+
+	timeout := e.ToolTimeout
+	if t, ok := e.ToolTimeouts[strings.ToUpper(tool.Name())]; ok {
+		timeout = t
+	}
+	observation, err := callWithTimeout(ctx, tool, input, timeout)
+	if err != nil {
+		if e.CallbacksHandler != nil {
+			e.CallbacksHandler.HandleToolError(ctx, err)
+		}
+		return "", err
+	}
+
+	if e.CallbacksHandler != nil {
+		e.CallbacksHandler.HandleToolEnd(ctx, observation)
+	}
+	return observation, nil
+}
+
+type toolResult struct {
+	observation string
+	err         error
+}
+
+// callWithTimeout calls a tool, returning an error wrapping ErrToolTimeout if
+// it does not return within the timeout, even if it does not honor the
+// cancellation of its context. A zero timeout only honors ctx.
+func callWithTimeout(ctx context.Context, tool tools.Tool, input string, timeout time.Duration) (string, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeoutCause(ctx, timeout, ErrToolTimeout)
+		defer cancel()
+	}
+
+	results := make(chan toolResult, 1)
+	go func() {
+		observation, err := tool.Call(ctx, input)
+		results <- toolResult{observation: observation, err: err}
+	}()
+
+	var (
+		observation string
+		err         error
+	)
+	select {
+	case result := <-results:
+		observation, err = result.observation, result.err
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	if err != nil && errors.Is(context.Cause(ctx), ErrToolTimeout) {
+		return "", fmt.Errorf("%w: %s did not return within %s", ErrToolTimeout, tool.Name(), timeout)
+	}
+	return observation, err
+}
@@ -0,0 +1,265 @@
+package agents_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/agents"
+	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/chains"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/tools"
+)
+
+// flakyTool fails a number of times before succeeding.
+type flakyTool struct {
+	failures int
+
+	calls int
+}
+
+func (t *flakyTool) Name() string {
+	return "flaky"
+}
+
+func (t *flakyTool) Description() string {
+	return "A tool that fails."
+}
+
+func (t *flakyTool) Call(context.Context, string) (string, error) {
+	t.calls++
+	if t.calls <= t.failures {
+		return "", errors.New("boom")
+	}
+	return "ok", nil
+}
+
+// blockingTool blocks until it is released, ignoring the cancellation of its
+// context.
+type blockingTool struct {
+	release chan struct{}
+}
+
+func (t blockingTool) Name() string {
+	return "blocking"
+}
+
+func (t blockingTool) Description() string {
+	return "A tool that blocks."
+}
+
+func (t blockingTool) Call(context.Context, string) (string, error) {
+	<-t.release
+	return "done", nil
+}
+
+type toolCallbacksHandler struct {
+	callbacks.SimpleHandler
+
+	mu     sync.Mutex
+	events []string
+}
+
+func (h *toolCallbacksHandler) HandleToolStart(context.Context, string) {
+	h.record("start")
+}
+
+func (h *toolCallbacksHandler) HandleToolEnd(context.Context, string) {
+	h.record("end")
+}
+
+func (h *toolCallbacksHandler) HandleToolError(context.Context, error) {
+	h.record("error")
+}
+
+func (h *toolCallbacksHandler) record(event string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.events = append(h.events, event)
+}
+
+func TestExecutorToolErrorPolicies(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name            string
+		failures        int
+		handler         *agents.ToolErrorHandler
+		wantErr         bool
+		wantObservation string
+		wantEvents      []string
+	}{
+		{
+			name:       "abort",
+			failures:   1,
+			wantErr:    true,
+			wantEvents: []string{"start", "error"},
+		},
+		{
+			name:            "retry",
+			failures:        2,
+			handler:         agents.NewToolErrorHandler(agents.ToolErrorAbort, 2),
+			wantObservation: "ok",
+			wantEvents:      []string{"start", "error", "start", "error", "start", "end"},
+		},
+		{
+			name:            "observe",
+			failures:        3,
+			handler:         agents.NewToolErrorHandler(agents.ToolErrorObserve, 1),
+			wantObservation: "Error: boom",
+			wantEvents:      []string{"start", "error", "start", "error"},
+		},
+		{
+			name:     "observe with formatter",
+			failures: 1,
+			handler: &agents.ToolErrorHandler{
+				Policy:    agents.ToolErrorObserve,
+				Formatter: func(err error) string { return "The tool failed: " + err.Error() },
+			},
+			wantObservation: "The tool failed: boom",
+			wantEvents:      []string{"start", "error"},
+		},
+	}
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			a := &testAgent{
+				actions: []schema.AgentAction{{Tool: "flaky"}},
+				tools:   []tools.Tool{&flakyTool{failures: tc.failures}},
+			}
+			handler := &toolCallbacksHandler{}
+			executor := agents.NewExecutor(a,
+				agents.WithMaxIterations(1),
+				agents.WithCallbacksHandler(handler),
+				agents.WithToolErrorHandler(tc.handler),
+				agents.WithReturnIntermediateSteps(),
+			)
+
+			result, err := executor.Call(context.Background(), nil)
+			assert.Equal(t, tc.wantEvents, handler.events)
+			if tc.wantErr {
+				require.ErrorContains(t, err, "boom")
+				return
+			}
+			require.ErrorIs(t, err, agents.ErrNotFinished)
+			steps := result["intermediateSteps"].([]schema.AgentStep)
+			require.Len(t, steps, 1)
+			assert.Equal(t, tc.wantObservation, steps[0].Observation)
+		})
+	}
+}
+
+func TestExecutorToolCallbacksReportedOnce(t *testing.T) {
+	t.Parallel()
+
+	handler := &toolCallbacksHandler{}
+	runs := callbacks.NewRunTreeHandler()
+	combined := callbacks.CombiningHandler{Callbacks: []callbacks.Handler{handler, runs}}
+	a := &testAgent{
+		actions: []schema.AgentAction{{Tool: "calculator", ToolInput: "1 + 1"}},
+		tools:   []tools.Tool{tools.Calculator{CallbacksHandler: combined}},
+	}
+	executor := agents.NewExecutor(a,
+		agents.WithMaxIterations(1),
+		agents.WithCallbacksHandler(combined),
+	)
+
+	_, err := chains.Call(context.Background(), executor, nil)
+	require.ErrorIs(t, err, agents.ErrNotFinished)
+	assert.Equal(t, []string{"start", "end"}, handler.events)
+
+	var toolRuns []*callbacks.Run
+	var collect func(run *callbacks.Run)
+	collect = func(run *callbacks.Run) {
+		if run.Type == callbacks.RunTypeTool {
+			toolRuns = append(toolRuns, run)
+		}
+		for _, child := range run.Children {
+			collect(child)
+		}
+	}
+	for _, run := range runs.Runs() {
+		collect(run)
+	}
+	require.Len(t, toolRuns, 1)
+	assert.Equal(t, "2", toolRuns[0].Outputs["output"])
+
+	// Without a callbacks handler on the executor, the tool reports its call.
+	handler.events = nil
+	executor = agents.NewExecutor(a, agents.WithMaxIterations(1))
+	_, err = chains.Call(context.Background(), executor, nil)
+	require.ErrorIs(t, err, agents.ErrNotFinished)
+	assert.Equal(t, []string{"start", "end"}, handler.events)
+}
+
+func TestExecutorToolCallbacksDifferentHandlers(t *testing.T) {
+	t.Parallel()
+
+	toolHandler := &toolCallbacksHandler{}
+	executorHandler := &toolCallbacksHandler{}
+	a := &testAgent{
+		actions: []schema.AgentAction{{Tool: "calculator", ToolInput: "1 + 1"}},
+		tools:   []tools.Tool{tools.Calculator{CallbacksHandler: toolHandler}},
+	}
+	executor := agents.NewExecutor(a,
+		agents.WithMaxIterations(1),
+		agents.WithCallbacksHandler(executorHandler),
+	)
+
+	// Both handlers are told about the call, each once.
+	_, err := chains.Call(context.Background(), executor, nil)
+	require.ErrorIs(t, err, agents.ErrNotFinished)
+	assert.Equal(t, []string{"start", "end"}, toolHandler.events)
+	assert.Equal(t, []string{"start", "end"}, executorHandler.events)
+}
+
+func TestExecutorToolTimeout(t *testing.T) {
+	t.Parallel()
+
+	tool := blockingTool{release: make(chan struct{})}
+	defer close(tool.release)
+	a := &testAgent{
+		actions: []schema.AgentAction{{Tool: "blocking"}},
+		tools:   []tools.Tool{tool},
+	}
+	executor := agents.NewExecutor(a,
+		agents.WithMaxIterations(1),
+		agents.WithToolTimeout(time.Hour),
+		// Tool names are case insensitive.
+		agents.WithToolTimeout(10*time.Millisecond, "Blocking"),
+		agents.WithToolErrorHandler(agents.NewToolErrorHandler(agents.ToolErrorObserve, 0)),
+		agents.WithReturnIntermediateSteps(),
+	)
+
+	result, err := executor.Call(context.Background(), nil)
+	require.ErrorIs(t, err, agents.ErrNotFinished)
+	steps := result["intermediateSteps"].([]schema.AgentStep)
+	require.Len(t, steps, 1)
+	assert.Equal(t, "Error: tool call timed out: blocking did not return within 10ms", steps[0].Observation)
+}
+
+func TestExecutorRunTimeout(t *testing.T) {
+	t.Parallel()
+
+	tool := blockingTool{release: make(chan struct{})}
+	defer close(tool.release)
+	a := &testAgent{
+		actions: []schema.AgentAction{{Tool: "blocking"}},
+		tools:   []tools.Tool{tool},
+	}
+	executor := agents.NewExecutor(a,
+		agents.WithRunTimeout(10*time.Millisecond),
+		agents.WithToolErrorHandler(agents.NewToolErrorHandler(agents.ToolErrorObserve, 3)),
+	)
+
+	_, err := chains.Call(context.Background(), executor, nil)
+	require.ErrorIs(t, err, agents.ErrRunTimeout)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,416 @@
+package callbacks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"golang.org/x/exp/maps"
+)
+
+// ErrBudgetExceeded is the cause of the cancellation of a context whose budget
+// was exceeded. The cause returned by context.Cause is a *BudgetExceededError
+// wrapping it.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// _unknownModel is the model under which the usage of responses that do not
+// report their model is recorded.
+const _unknownModel = "unknown"
+
+// ModelPrice is the price of a model, in US dollars per million tokens.
+type ModelPrice struct {
+	Input  float64 `json:"input"`
+	Output float64 `json:"output"`
+}
+
+// Cost returns the cost, in US dollars, of the given numbers of tokens.
+func (p ModelPrice) Cost(inputTokens, outputTokens int) float64 {
+	return (float64(inputTokens)*p.Input + float64(outputTokens)*p.Output) / 1e6
+}
+
+// PricingTable maps model names to their prices.
+type PricingTable map[string]ModelPrice
+
+// Lookup returns the price of a model. Any path before the model name, as in
+// "models/gemini-1.5-pro", is ignored. Models that are not in the table are
+// looked up by the longest name of the table that is a prefix of theirs
+// followed by a version, so that "gpt-4o-2024-05-13" or
+// "claude-3-opus@20240229" are priced as "gpt-4o" and "claude-3-opus".
+func (t PricingTable) Lookup(model string) (ModelPrice, bool) {
+	model = model[strings.LastIndex(model, "/")+1:]
+	if price, ok := t[model]; ok {
+		return price, true
+	}
+	var (
+		price ModelPrice
+		found string
+	)
+	for name, p := range t {
+		if len(name) <= len(found) || !strings.HasPrefix(model, name) {
+			continue
+		}
+		if strings.ContainsRune("-@:", rune(model[len(name)])) {
+			price, found = p, name
+		}
+	}
+	return price, found != ""
+}
+
+// _defaultPrices are the list prices of the models of the providers, in US
+// dollars per million tokens.
+var _defaultPrices = PricingTable{ //nolint:gochecknoglobals
+	// OpenAI.
+	"gpt-4o":                 {Input: 5, Output: 15},
+	"gpt-4o-mini":            {Input: 0.15, Output: 0.6},
+	"gpt-4-turbo":            {Input: 10, Output: 30},
+	"gpt-4-turbo-preview":    {Input: 10, Output: 30},
+	"gpt-4":                  {Input: 30, Output: 60},
+	"gpt-4-32k":              {Input: 60, Output: 120},
+	"gpt-3.5-turbo":          {Input: 0.5, Output: 1.5},
+	"gpt-3.5-turbo-instruct": {Input: 1.5, Output: 2},
+	"o1-preview":             {Input: 15, Output: 60},
+	"o1-mini":                {Input: 3, Output: 12},
+	// Anthropic.
+	"claude-3-5-sonnet":  {Input: 3, Output: 15},
+	"claude-3-opus":      {Input: 15, Output: 75},
+	"claude-3-sonnet":    {Input: 3, Output: 15},
+	"claude-3-haiku":     {Input: 0.25, Output: 1.25},
+	"claude-2.1":         {Input: 8, Output: 24},
+	"claude-2.0":         {Input: 8, Output: 24},
+	"claude-instant-1.2": {Input: 0.8, Output: 2.4},
+	// Google.
+	"gemini-1.5-pro":   {Input: 3.5, Output: 10.5},
+	"gemini-1.5-flash": {Input: 0.35, Output: 1.05},
+	"gemini-1.0-pro":   {Input: 0.5, Output: 1.5},
+	"gemini-pro":       {Input: 0.5, Output: 1.5},
+	// Mistral.
+	"open-mistral-7b":      {Input: 0.25, Output: 0.25},
+	"open-mixtral-8x7b":    {Input: 0.7, Output: 0.7},
+	"open-mixtral-8x22b":   {Input: 2, Output: 6},
+	"mistral-small":        {Input: 1, Output: 3},
+	"mistral-medium":       {Input: 2.7, Output: 8.1},
+	"mistral-large":        {Input: 4, Output: 12},
+	"codestral":            {Input: 1, Output: 3},
+	"open-mistral-nemo":    {Input: 0.3, Output: 0.3},
+	"open-codestral-mamba": {Input: 0.25, Output: 0.25},
+}
+
+// DefaultPricingTable returns a new pricing table with the list prices of the
+// models of the OpenAI, Anthropic, Google AI, Vertex AI and Mistral providers,
+// which report the model and token usage of their responses. The prices may be
+// outdated: the table can be extended or corrected with WithModelPrice.
+func DefaultPricingTable() PricingTable {
+	return maps.Clone(_defaultPrices)
+}
+
+// Usage is the token usage and cost of LLM calls.
+type Usage struct {
+	Calls        int `json:"calls"`
+	InputTokens  int `json:"input_tokens"`
+	OutputTokens int `json:"output_tokens"`
+	// Cost is the cost of the calls in US dollars. Calls to models missing
+	// from the pricing table cost nothing.
+	Cost float64 `json:"cost"`
+}
+
+// TotalTokens returns the number of input and output tokens.
+func (u Usage) TotalTokens() int {
+	return u.InputTokens + u.OutputTokens
+}
+
+func (u *Usage) add(other Usage) {
+	u.Calls += other.Calls
+	u.InputTokens += other.InputTokens
+	u.OutputTokens += other.OutputTokens
+	u.Cost += other.Cost
+}
+
+// Budget limits the usage of the LLM calls made with a context. Zero limits
+// are ignored.
+type Budget struct {
+	// MaxCost is the maximum cost, in US dollars.
+	MaxCost float64
+	// MaxTokens is the maximum number of input and output tokens.
+	MaxTokens int
+}
+
+func (b Budget) exceeded(u Usage) bool {
+	return (b.MaxCost > 0 && u.Cost > b.MaxCost) || (b.MaxTokens > 0 && u.TotalTokens() > b.MaxTokens)
+}
+
+// BudgetExceededError is the cause of the cancellation of a context whose
+// budget was exceeded.
+type BudgetExceededError struct {
+	Budget Budget
+	Usage  Usage
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("%s: used %d tokens costing $%.4f, budget is %d tokens and $%.4f",
+		ErrBudgetExceeded, e.Usage.TotalTokens(), e.Usage.Cost, e.Budget.MaxTokens, e.Budget.MaxCost)
+}
+
+func (e *BudgetExceededError) Unwrap() error {
+	return ErrBudgetExceeded
+}
+
+type sessionKey struct{}
+
+// ContextWithSession returns a copy of ctx with a session key, under which
+// the CostHandler records the usage of the LLM calls made with it.
+func ContextWithSession(ctx context.Context, session string) context.Context {
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+// SessionFromContext returns the session key of ctx, if any.
+func SessionFromContext(ctx context.Context) (string, bool) {
+	session, ok := ctx.Value(sessionKey{}).(string)
+	return session, ok
+}
+
+// CostHandler is a callback handler that aggregates the token usage of LLM
+// calls, prices it with a pricing table, and records running totals per
+// model, per chain and per session. It also guards budgets set with
+// WithBudget.
+//
+// The model of a call is the one reported in the generation info of its
+// response. The usage of a call is recorded for every chain the call is made
+// within, by name, so the usage of the chains of an agent is included in the
+// usage of its executor.
+type CostHandler struct {
+	SimpleHandler
+
+	mu       sync.Mutex
+	pricing  PricingTable
+	total    Usage
+	models   map[string]Usage
+	chains   map[string]Usage
+	sessions map[string]Usage
+	runs     map[string]RunInfo
+}
+
+var _ Handler = &CostHandler{}
+
+// CostOption is an option for the CostHandler.
+type CostOption func(h *CostHandler)
+
+// WithPricingTable sets the pricing table of the handler, which is copied.
+// The default is DefaultPricingTable().
+func WithPricingTable(table PricingTable) CostOption {
+	return func(h *CostHandler) {
+		h.pricing = maps.Clone(table)
+	}
+}
+
+// WithModelPrice sets the price of a model in the pricing table of the
+// handler.
+func WithModelPrice(model string, price ModelPrice) CostOption {
+	return func(h *CostHandler) {
+		if h.pricing == nil {
+			h.pricing = PricingTable{}
+		}
+		h.pricing[model] = price
+	}
+}
+
+// NewCostHandler creates a new cost tracking callback handler.
+func NewCostHandler(opts ...CostOption) *CostHandler {
+	h := &CostHandler{pricing: DefaultPricingTable()}
+	for _, opt := range opts {
+		opt(h)
+	}
+	h.reset()
+	return h
+}
+
+// Total returns the usage of all the calls recorded so far.
+func (h *CostHandler) Total() Usage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.total
+}
+
+// Models returns the usage recorded so far per model.
+func (h *CostHandler) Models() map[string]Usage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return maps.Clone(h.models)
+}
+
+// Chains returns the usage recorded so far per chain name.
+func (h *CostHandler) Chains() map[string]Usage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return maps.Clone(h.chains)
+}
+
+// Sessions returns the usage recorded so far per session key.
+func (h *CostHandler) Sessions() map[string]Usage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return maps.Clone(h.sessions)
+}
+
+// Reset removes the usage recorded so far.
+func (h *CostHandler) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.reset()
+}
+
+func (h *CostHandler) reset() {
+	h.total = Usage{}
+	h.models = map[string]Usage{}
+	h.chains = map[string]Usage{}
+	h.sessions = map[string]Usage{}
+	h.runs = map[string]RunInfo{}
+}
+
+// budget is a budget guarded by a CostHandler, with the usage of the calls
+// made with its context.
+type budget struct {
+	Budget
+	usage  Usage
+	cancel context.CancelCauseFunc
+	parent *budget
+}
+
+type budgetKey struct {
+	h *CostHandler
+}
+
+// WithBudget returns a copy of ctx that the handler cancels, with a
+// *BudgetExceededError as cause, as soon as the LLM calls made with it exceed
+// the budget. Budgets can be nested, each being checked against the calls
+// made within it. Canceling the returned context releases its resources.
+func (h *CostHandler) WithBudget(ctx context.Context, b Budget) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	parent, _ := ctx.Value(budgetKey{h}).(*budget)
+	ctx = context.WithValue(ctx, budgetKey{h}, &budget{Budget: b, cancel: cancel, parent: parent})
+	return ctx, func() { cancel(nil) }
+}
+
+// Spent returns the usage of the calls made within the innermost budget of
+// ctx, if any.
+func (h *CostHandler) Spent(ctx context.Context) (Usage, bool) {
+	b, ok := ctx.Value(budgetKey{h}).(*budget)
+	if !ok {
+		return Usage{}, false
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return b.usage, true
+}
+
+func (h *CostHandler) HandleLLMGenerateContentEnd(ctx context.Context, res *llms.ContentResponse) {
+	tokens := responseTokenUsage(res)
+	model := responseModel(res)
+	usage := Usage{Calls: 1, InputTokens: tokens.input, OutputTokens: tokens.output}
+	if price, ok := h.pricing.Lookup(model); ok {
+		usage.Cost = price.Cost(tokens.input, tokens.output)
+	}
+	if model == "" {
+		model = _unknownModel
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.total.add(usage)
+	addUsage(h.models, model, usage)
+	for _, chain := range h.chainNames(ctx) {
+		addUsage(h.chains, chain, usage)
+	}
+	if session, ok := SessionFromContext(ctx); ok {
+		addUsage(h.sessions, session, usage)
+	}
+
+	for b, _ := ctx.Value(budgetKey{h}).(*budget); b != nil; b = b.parent {
+		b.usage.add(usage)
+		if b.exceeded(b.usage) {
+			b.cancel(&BudgetExceededError{Budget: b.Budget, Usage: b.usage})
+		}
+	}
+}
+
+func (h *CostHandler) HandleChainStart(ctx context.Context, _ map[string]any) {
+	h.startRun(ctx, RunTypeChain)
+}
+
+func (h *CostHandler) HandleChainEnd(ctx context.Context, _ map[string]any) {
+	h.endRun(ctx, RunTypeChain)
+}
+
+func (h *CostHandler) HandleChainError(ctx context.Context, _ error) {
+	h.endRun(ctx, RunTypeChain)
+}
+
+func (h *CostHandler) HandleToolStart(ctx context.Context, _ string) {
+	h.startRun(ctx, RunTypeTool)
+}
+
+func (h *CostHandler) HandleToolEnd(ctx context.Context, _ string) {
+	h.endRun(ctx, RunTypeTool)
+}
+
+func (h *CostHandler) HandleToolError(ctx context.Context, _ error) {
+	h.endRun(ctx, RunTypeTool)
+}
+
+// startRun records the run of ctx, if it is of the given type, to find the
+// chains LLM calls are made within.
+func (h *CostHandler) startRun(ctx context.Context, runType string) {
+	info, ok := RunFromContext(ctx)
+	if !ok || info.Type != runType {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.runs[info.ID] = info
+}
+
+func (h *CostHandler) endRun(ctx context.Context, runType string) {
+	info, ok := RunFromContext(ctx)
+	if !ok || info.Type != runType {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	delete(h.runs, info.ID)
+}
+
+// chainNames returns the names of the open chains ctx is within, innermost
+// first and without duplicates.
+func (h *CostHandler) chainNames(ctx context.Context) []string {
+	info, ok := RunFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	var names []string
+	seen := map[string]bool{}
+	for id := info.ID; id != ""; {
+		run, ok := h.runs[id]
+		if !ok {
+			if id == info.ID {
+				id = info.ParentID
+				continue
+			}
+			break
+		}
+		if run.Type == RunTypeChain && !seen[run.Name] {
+			seen[run.Name] = true
+			names = append(names, run.Name)
+		}
+		id = run.ParentID
+	}
+	return names
+}
+
+func addUsage(usages map[string]Usage, key string, usage Usage) {
+	u := usages[key]
+	u.add(usage)
+	usages[key] = u
+}
@@ -0,0 +1,135 @@
+package callbacks
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	mistral "github.com/gage-technologies/mistral-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func costResponse(model string, inputTokens, outputTokens int) *llms.ContentResponse {
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		GenerationInfo: map[string]any{"model": model, "PromptTokens": inputTokens, "CompletionTokens": outputTokens},
+	}}}
+}
+
+func TestPricingTableLookup(t *testing.T) {
+	t.Parallel()
+	table := DefaultPricingTable()
+
+	cases := map[string]string{
+		"gpt-4o":                  "gpt-4o",
+		"gpt-4o-2024-05-13":       "gpt-4o",
+		"gpt-4o-mini-2024-07-18":  "gpt-4o-mini",
+		"gpt-4-0613":              "gpt-4",
+		"claude-3-opus@20240229":  "claude-3-opus",
+		"models/gemini-1.5-flash": "gemini-1.5-flash",
+		"mistral-large-latest":    "mistral-large",
+	}
+	for model, name := range cases {
+		price, ok := table.Lookup(model)
+		assert.True(t, ok, model)
+		assert.Equal(t, table[name], price, model)
+	}
+
+	_, ok := table.Lookup("gpt-4oops")
+	assert.False(t, ok)
+	_, ok = table.Lookup("")
+	assert.False(t, ok)
+	assert.InDelta(t, 0.02, ModelPrice{Input: 5, Output: 15}.Cost(1000, 1000), 1e-9)
+}
+
+func TestCostHandlerTotals(t *testing.T) {
+	t.Parallel()
+	handler := NewCostHandler(WithModelPrice("custom", ModelPrice{Input: 1, Output: 2}))
+
+	ctx := ContextWithSession(context.Background(), "alice")
+	executorCtx := StartRun(ctx, RunTypeChain, "Executor")
+	handler.HandleChainStart(executorCtx, nil)
+	llmChainCtx := StartRun(executorCtx, RunTypeChain, "LLMChain")
+	handler.HandleChainStart(llmChainCtx, nil)
+	handler.HandleLLMGenerateContentEnd(StartRun(llmChainCtx, RunTypeLLM, "openai"), costResponse("gpt-4o", 1000, 100))
+	handler.HandleChainEnd(llmChainCtx, nil)
+	toolCtx := StartRun(executorCtx, RunTypeTool, "search")
+	handler.HandleToolStart(toolCtx, "")
+	handler.HandleLLMGenerateContentEnd(StartRun(toolCtx, RunTypeLLM, "custom"), costResponse("custom", 1000, 1000))
+	handler.HandleToolEnd(toolCtx, "")
+	handler.HandleChainEnd(executorCtx, nil)
+	handler.HandleLLMGenerateContentEnd(context.Background(), &llms.ContentResponse{})
+
+	total := handler.Total()
+	assert.Equal(t, 3, total.Calls)
+	assert.Equal(t, 3100, total.TotalTokens())
+	assert.InDelta(t, 0.0065+0.003, total.Cost, 1e-9)
+
+	models := handler.Models()
+	assert.Equal(t, Usage{Calls: 1, InputTokens: 1000, OutputTokens: 100, Cost: 0.0065}, models["gpt-4o"])
+	assert.Equal(t, Usage{Calls: 1}, models[_unknownModel])
+
+	chains := handler.Chains()
+	assert.Len(t, chains, 2)
+	assert.Equal(t, 2, chains["Executor"].Calls)
+	assert.Equal(t, 1, chains["LLMChain"].Calls)
+	assert.Equal(t, map[string]Usage{"alice": chains["Executor"]}, handler.Sessions())
+	assert.Empty(t, handler.runs)
+
+	handler.Reset()
+	assert.Equal(t, Usage{}, handler.Total())
+	assert.Empty(t, handler.Models())
+}
+
+func TestCostHandlerProviderUsage(t *testing.T) {
+	t.Parallel()
+	handler := NewCostHandler()
+
+	// Google AI and Vertex AI report int32 token counts.
+	handler.HandleLLMGenerateContentEnd(context.Background(), &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		GenerationInfo: map[string]any{"model": "gemini-1.5-flash", "input_tokens": int32(1000), "output_tokens": int32(1000)},
+	}}})
+	// Mistral reports its usage as a struct.
+	handler.HandleLLMGenerateContentEnd(context.Background(), &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		GenerationInfo: map[string]any{"model": "mistral-large-latest", "usage": mistral.UsageInfo{
+			PromptTokens: 1000, CompletionTokens: 1000, TotalTokens: 2000,
+		}},
+	}}})
+
+	models := handler.Models()
+	assert.InDelta(t, 0.0014, models["gemini-1.5-flash"].Cost, 1e-9)
+	assert.InDelta(t, 0.016, models["mistral-large-latest"].Cost, 1e-9)
+}
+
+func TestCostHandlerBudget(t *testing.T) {
+	t.Parallel()
+	handler := NewCostHandler()
+
+	ctx, cancel := handler.WithBudget(context.Background(), Budget{MaxCost: 1})
+	defer cancel()
+	innerCtx, innerCancel := handler.WithBudget(ctx, Budget{MaxTokens: 1500})
+	defer innerCancel()
+
+	handler.HandleLLMGenerateContentEnd(innerCtx, costResponse("gpt-4o", 1000, 0))
+	require.NoError(t, innerCtx.Err())
+	handler.HandleLLMGenerateContentEnd(innerCtx, costResponse("gpt-4o", 1000, 0))
+	require.Error(t, innerCtx.Err())
+	require.NoError(t, ctx.Err())
+
+	var budgetErr *BudgetExceededError
+	require.ErrorAs(t, context.Cause(innerCtx), &budgetErr)
+	assert.True(t, errors.Is(budgetErr, ErrBudgetExceeded))
+	assert.Equal(t, 2000, budgetErr.Usage.TotalTokens())
+
+	spent, ok := handler.Spent(ctx)
+	require.True(t, ok)
+	assert.InDelta(t, 0.01, spent.Cost, 1e-9)
+	handler.HandleLLMGenerateContentEnd(ctx, costResponse("gpt-4", 40000, 0))
+	require.ErrorIs(t, context.Cause(ctx), ErrBudgetExceeded)
+
+	_, ok = handler.Spent(context.Background())
+	assert.False(t, ok)
+	_, ok = NewCostHandler().Spent(ctx)
+	assert.False(t, ok)
+}
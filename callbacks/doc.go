@@ -1,4 +1,12 @@
 // Package callbacks includes a standard interface for hooking into various
 // stages of your LLM application. The package contains an implementation of
-// this interface that prints to the standard output.
+// this interface that prints to the standard output, one that logs structured
+// and redacted records with log/slog, one that traces the application and
+// records its metrics with OpenTelemetry, one that records the tree of the runs
+// of the application, and one that tracks the cost of the LLM calls and guards
+// budgets.
+//
+// The callbacks of a run of a chain, LLM, tool or retriever are called with a
+// context carrying a RunInfo, started with StartRun, that identifies the run
+// and its parent run.
 package callbacks
@@ -0,0 +1,449 @@
+package callbacks
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// _instrumentationName is the name of the tracer and meter of the
+// OpenTelemetryHandler.
+const _instrumentationName = "github.com/tmc/langchaingo/callbacks"
+
+// Attribute keys of the spans and metrics of the OpenTelemetryHandler.
+const (
+	AttributeRunType      = attribute.Key("langchaingo.run.type")
+	AttributeToolName     = attribute.Key("langchaingo.tool.name")
+	AttributeError        = attribute.Key("error")
+	AttributeModel        = attribute.Key("gen_ai.response.model")
+	AttributeInputTokens  = attribute.Key("gen_ai.usage.input_tokens")
+	AttributeOutputTokens = attribute.Key("gen_ai.usage.output_tokens")
+	AttributeTokenType    = attribute.Key("gen_ai.token.type")
+	AttributeMessages     = attribute.Key("langchaingo.llm.messages")
+	AttributeDocuments    = attribute.Key("langchaingo.retriever.documents")
+)
+
+// OpenTelemetryHandler is a callback handler that traces chains, LLM calls,
+// tools, retrievers and agent steps with OpenTelemetry spans, and records
+// their latency, token usage and errors as metrics.
+//
+// Spans are nested following the RunInfo of the context of the callbacks: the
+// span of a run is the child of the span of its parent run. A run started
+// while another run is open with the same context, such as a tool run for a
+// step of an agent, is its child, and a run without a parent run is a child
+// of the span of the context, if any. Prompts, outputs and other contents are
+// not recorded.
+type OpenTelemetryHandler struct {
+	SimpleHandler
+
+	tracer trace.Tracer
+
+	duration metric.Float64Histogram
+	tokens   metric.Int64Counter
+	errors   metric.Int64Counter
+
+	mu sync.Mutex
+	// runs are the open runs by the context they were started with.
+	runs map[context.Context][]*otelRun
+	// runContexts are the contexts of the open runs with a RunInfo, by ID.
+	runContexts map[string]context.Context
+	// stopAbandon unregisters, by context, the function ending the runs still
+	// open when the context is done.
+	stopAbandon map[context.Context]func() bool
+}
+
+var _ Handler = &OpenTelemetryHandler{}
+
+// otelRun is an open span of the OpenTelemetryHandler.
+type otelRun struct {
+	// id is the ID of the RunInfo of the run, if its caller started one.
+	id       string
+	runType  string
+	toolName string
+	span     trace.Span
+	start    time.Time
+	// parent is the context with which the parent run was started, if any.
+	parent context.Context //nolint:containedctx
+}
+
+type otelOptions struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// OpenTelemetryOption is an option for the OpenTelemetryHandler.
+type OpenTelemetryOption func(opts *otelOptions)
+
+// WithTracerProvider sets the tracer provider of the handler. The default is
+// the global tracer provider.
+func WithTracerProvider(provider trace.TracerProvider) OpenTelemetryOption {
+	return func(opts *otelOptions) {
+		opts.tracerProvider = provider
+	}
+}
+
+// WithMeterProvider sets the meter provider of the handler. The default is the
+// global meter provider.
+func WithMeterProvider(provider metric.MeterProvider) OpenTelemetryOption {
+	return func(opts *otelOptions) {
+		opts.meterProvider = provider
+	}
+}
+
+// NewOpenTelemetryHandler creates a new OpenTelemetry callback handler. It
+// records the following metrics:
+//   - langchaingo.run.duration: a histogram of the duration of the runs, in
+//     seconds, by run type, tool name and error.
+//   - langchaingo.llm.tokens: a counter of the tokens used by LLM calls, by
+//     model and token type, "input" or "output".
+//   - langchaingo.run.errors: a counter of the runs that failed, by run type.
+func NewOpenTelemetryHandler(opts ...OpenTelemetryOption) (*OpenTelemetryHandler, error) {
+	options := otelOptions{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	meter := options.meterProvider.Meter(_instrumentationName)
+	duration, err := meter.Float64Histogram("langchaingo.run.duration",
+		metric.WithDescription("Duration of the runs of chains, LLM calls, tools, retrievers and agent steps."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := meter.Int64Counter("langchaingo.llm.tokens",
+		metric.WithDescription("Number of tokens used by LLM calls."),
+		metric.WithUnit("{token}"))
+	if err != nil {
+		return nil, err
+	}
+	errors, err := meter.Int64Counter("langchaingo.run.errors",
+		metric.WithDescription("Number of runs that failed."),
+		metric.WithUnit("{error}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return &OpenTelemetryHandler{
+		tracer:      options.tracerProvider.Tracer(_instrumentationName),
+		duration:    duration,
+		tokens:      tokens,
+		errors:      errors,
+		runs:        map[context.Context][]*otelRun{},
+		runContexts: map[string]context.Context{},
+		stopAbandon: map[context.Context]func() bool{},
+	}, nil
+}
+
+func (h *OpenTelemetryHandler) HandleLLMGenerateContentStart(ctx context.Context, ms []llms.MessageContent) {
+	h.start(ctx, RunTypeLLM, "llm", AttributeMessages.Int(len(ms)))
+}
+
+func (h *OpenTelemetryHandler) HandleLLMGenerateContentEnd(ctx context.Context, res *llms.ContentResponse) {
+	model := responseModel(res)
+	usage := responseTokenUsage(res)
+	var attrs []attribute.KeyValue
+	if model != "" {
+		attrs = append(attrs, AttributeModel.String(model))
+	}
+	if usage.found {
+		attrs = append(attrs, AttributeInputTokens.Int(usage.input), AttributeOutputTokens.Int(usage.output))
+		metricAttrs := []attribute.KeyValue{AttributeModel.String(model)}
+		h.tokens.Add(ctx, int64(usage.input),
+			metric.WithAttributes(append(metricAttrs, AttributeTokenType.String("input"))...))
+		h.tokens.Add(ctx, int64(usage.output),
+			metric.WithAttributes(append(metricAttrs, AttributeTokenType.String("output"))...))
+	}
+	h.end(ctx, RunTypeLLM, nil, attrs...)
+}
+
+func (h *OpenTelemetryHandler) HandleLLMError(ctx context.Context, err error) {
+	h.end(ctx, RunTypeLLM, err)
+}
+
+func (h *OpenTelemetryHandler) HandleChainStart(ctx context.Context, _ map[string]any) {
+	h.start(ctx, RunTypeChain, "chain")
+}
+
+func (h *OpenTelemetryHandler) HandleChainEnd(ctx context.Context, _ map[string]any) {
+	h.end(ctx, RunTypeChain, nil)
+}
+
+func (h *OpenTelemetryHandler) HandleChainError(ctx context.Context, err error) {
+	h.end(ctx, RunTypeChain, err)
+}
+
+func (h *OpenTelemetryHandler) HandleToolStart(ctx context.Context, _ string) {
+	h.start(ctx, RunTypeTool, "tool")
+}
+
+func (h *OpenTelemetryHandler) HandleToolEnd(ctx context.Context, _ string) {
+	h.endTool(ctx, nil)
+}
+
+func (h *OpenTelemetryHandler) HandleToolError(ctx context.Context, err error) {
+	h.endTool(ctx, err)
+}
+
+// endTool ends the span of a tool and, if the tool was run for a step of an
+// agent, the span of the step.
+func (h *OpenTelemetryHandler) endTool(ctx context.Context, err error) {
+	run := h.end(ctx, RunTypeTool, err)
+	if run == nil || run.parent == nil {
+		return
+	}
+	if parent := h.current(run.parent); parent != nil && parent.runType == RunTypeAgentStep {
+		h.end(run.parent, RunTypeAgentStep, nil)
+	}
+}
+
+func (h *OpenTelemetryHandler) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	// A step lasts until its tool ends, or until the next action or the end of
+	// the agent for tools that do not report their runs.
+	h.end(ctx, RunTypeAgentStep, nil)
+	h.startRun(ctx, &otelRun{runType: RunTypeAgentStep, toolName: action.Tool}, "agent_step")
+}
+
+func (h *OpenTelemetryHandler) HandleAgentFinish(ctx context.Context, _ schema.AgentFinish) {
+	h.end(ctx, RunTypeAgentStep, nil)
+	if current := h.current(ctx); current != nil {
+		current.span.AddEvent("agent_finish")
+	}
+}
+
+func (h *OpenTelemetryHandler) HandleRetrieverStart(ctx context.Context, _ string) {
+	h.start(ctx, RunTypeRetriever, "retriever")
+}
+
+func (h *OpenTelemetryHandler) HandleRetrieverEnd(ctx context.Context, _ string, documents []schema.Document) {
+	h.end(ctx, RunTypeRetriever, nil, AttributeDocuments.Int(len(documents)))
+}
+
+// start starts a span for a run.
+func (h *OpenTelemetryHandler) start(ctx context.Context, runType, name string, attrs ...attribute.KeyValue) {
+	h.startRun(ctx, &otelRun{runType: runType}, name, attrs...)
+}
+
+// startRun starts the span of a run, child of the span of the innermost run
+// open with ctx or, if there is none, of the innermost run open with the
+// context of the parent run of the RunInfo of ctx.
+func (h *OpenTelemetryHandler) startRun(ctx context.Context, run *otelRun, name string, attrs ...attribute.KeyValue) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var parentRunID string
+	if info, ok := RunFromContext(ctx); ok {
+		parentRunID = info.ID
+		if info.Type == run.runType {
+			run.id, parentRunID = info.ID, info.ParentID
+			h.runContexts[info.ID] = ctx
+			if info.Name != "" {
+				name += " " + info.Name
+				if run.runType == RunTypeTool {
+					run.toolName = info.Name
+				}
+			}
+		}
+	}
+	if len(h.runs[ctx]) > 0 {
+		run.parent = ctx
+	} else if parentCtx, ok := h.runContexts[parentRunID]; ok && len(h.runs[parentCtx]) > 0 {
+		run.parent = parentCtx
+	}
+
+	parent := ctx
+	if run.parent != nil {
+		parentRuns := h.runs[run.parent]
+		parentRun := parentRuns[len(parentRuns)-1]
+		parent = trace.ContextWithSpan(ctx, parentRun.span)
+		// The tool of a step of an agent is the tool of its action.
+		if run.runType == RunTypeTool && run.toolName == "" && parentRun.runType == RunTypeAgentStep {
+			run.toolName = parentRun.toolName
+		}
+	}
+
+	attrs = append(attrs, AttributeRunType.String(run.runType))
+	if run.toolName != "" {
+		attrs = append(attrs, AttributeToolName.String(run.toolName))
+	}
+	_, run.span = h.tracer.Start(parent, name, trace.WithAttributes(attrs...))
+	run.start = time.Now()
+	if len(h.runs[ctx]) == 0 {
+		// Runs whose end is never reported, because of a panic, a provider
+		// not reporting its errors or a canceled stream, are ended when ctx
+		// is done, so that ctx is not kept forever.
+		h.stopAbandon[ctx] = context.AfterFunc(ctx, func() { h.abandon(ctx) })
+	}
+	h.runs[ctx] = append(h.runs[ctx], run)
+}
+
+// abandon ends the runs still open with ctx once it is done, with the cause of
+// ctx as error.
+func (h *OpenTelemetryHandler) abandon(ctx context.Context) {
+	h.mu.Lock()
+	runs := h.runs[ctx]
+	h.mu.Unlock()
+	if len(runs) > 0 {
+		h.end(ctx, runs[0].runType, context.Cause(ctx))
+	}
+}
+
+// end ends the span of the innermost open run of the given type started with
+// ctx, and the spans of the runs started within it that were not ended. It
+// returns the run, or nil if there is no such run.
+func (h *OpenTelemetryHandler) end(ctx context.Context, runType string, err error, attrs ...attribute.KeyValue) *otelRun { //nolint:lll
+	h.mu.Lock()
+	runs := h.runs[ctx]
+	i := len(runs) - 1
+	for i >= 0 && runs[i].runType != runType {
+		i--
+	}
+	if i < 0 {
+		h.mu.Unlock()
+		return nil
+	}
+	ended := runs[i:]
+	if i == 0 {
+		delete(h.runs, ctx)
+		h.stopAbandon[ctx]()
+		delete(h.stopAbandon, ctx)
+	} else {
+		h.runs[ctx] = runs[:i:i]
+	}
+	for _, run := range ended {
+		if run.id != "" {
+			delete(h.runContexts, run.id)
+		}
+	}
+	h.mu.Unlock()
+
+	for j := len(ended) - 1; j >= 0; j-- {
+		run := ended[j]
+		if j == 0 {
+			run.span.SetAttributes(attrs...)
+			if err != nil {
+				run.span.RecordError(err)
+				run.span.SetStatus(codes.Error, err.Error())
+				h.errors.Add(ctx, 1, metric.WithAttributes(AttributeRunType.String(run.runType)))
+			}
+		}
+		run.span.End()
+
+		metricAttrs := []attribute.KeyValue{
+			AttributeRunType.String(run.runType),
+			AttributeError.Bool(j == 0 && err != nil),
+		}
+		if run.toolName != "" {
+			metricAttrs = append(metricAttrs, AttributeToolName.String(run.toolName))
+		}
+		h.duration.Record(ctx, time.Since(run.start).Seconds(), metric.WithAttributes(metricAttrs...))
+	}
+	return ended[0]
+}
+
+// current returns the innermost open run started with ctx, or nil.
+func (h *OpenTelemetryHandler) current(ctx context.Context) *otelRun {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	runs := h.runs[ctx]
+	if len(runs) == 0 {
+		return nil
+	}
+	return runs[len(runs)-1]
+}
+
+// tokenUsage is the number of tokens used by an LLM call.
+type tokenUsage struct {
+	input  int
+	output int
+	found  bool
+}
+
+// responseTokenUsage returns the token usage reported in the generation info
+// of a response. LLMs report the usage of the whole call in each choice, so the
+// usage of the first choice reporting it is returned.
+func responseTokenUsage(res *llms.ContentResponse) tokenUsage {
+	if res == nil {
+		return tokenUsage{}
+	}
+	for _, choice := range res.Choices {
+		if choice == nil {
+			continue
+		}
+		// Some providers, such as Mistral, report their usage as a struct.
+		for _, info := range []map[string]any{choice.GenerationInfo, structFields(choice.GenerationInfo["usage"])} {
+			input, inputFound := generationInfoInt(info,
+				"PromptTokens", "InputTokens", "prompt_tokens", "input_tokens")
+			output, outputFound := generationInfoInt(info,
+				"CompletionTokens", "OutputTokens", "completion_tokens", "output_tokens")
+			if inputFound || outputFound {
+				return tokenUsage{input: input, output: output, found: true}
+			}
+		}
+	}
+	return tokenUsage{}
+}
+
+// structFields returns the exported fields of a struct, or of a pointer to a
+// struct, by name, or nil if v is not a struct.
+func structFields(v any) map[string]any {
+	rv := reflect.Indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := make(map[string]any, rv.NumField())
+	for i := 0; i < rv.NumField(); i++ {
+		if field := rv.Type().Field(i); field.IsExported() {
+			fields[field.Name] = rv.Field(i).Interface()
+		}
+	}
+	return fields
+}
+
+// responseModel returns the model reported in the generation info of a
+// response, if any.
+func responseModel(res *llms.ContentResponse) string {
+	if res == nil {
+		return ""
+	}
+	for _, choice := range res.Choices {
+		if choice == nil {
+			continue
+		}
+		for _, key := range []string{"model", "Model"} {
+			if model, ok := choice.GenerationInfo[key].(string); ok && model != "" {
+				return model
+			}
+		}
+	}
+	return ""
+}
+
+// generationInfoInt returns the first of the keys of the generation info that
+// has an integer value.
+func generationInfoInt(info map[string]any, keys ...string) (int, bool) {
+	for _, key := range keys {
+		switch v := info[key].(type) {
+		case int:
+			return v, true
+		case int32:
+			return int(v), true
+		case int64:
+			return int(v), true
+		case float64:
+			return int(v), true
+		}
+	}
+	return 0, false
+}
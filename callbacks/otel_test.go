@@ -0,0 +1,181 @@
+package callbacks
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestOpenTelemetryHandler(t *testing.T) (*OpenTelemetryHandler, *tracetest.InMemoryExporter, *sdkmetric.ManualReader) { //nolint:lll
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	reader := sdkmetric.NewManualReader()
+	handler, err := NewOpenTelemetryHandler(
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	require.NoError(t, err)
+	return handler, exporter, reader
+}
+
+func TestOpenTelemetryHandlerSpans(t *testing.T) {
+	t.Parallel()
+	handler, exporter, _ := newTestOpenTelemetryHandler(t)
+	ctx := context.Background()
+
+	handler.HandleChainStart(ctx, nil)
+	handler.HandleLLMGenerateContentStart(ctx, []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "hi")})
+	handler.HandleLLMGenerateContentEnd(ctx, &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		GenerationInfo: map[string]any{"model": "test-model", "PromptTokens": 10, "CompletionTokens": 4},
+	}}})
+	handler.HandleAgentAction(ctx, schema.AgentAction{Tool: "calculator"})
+	handler.HandleToolStart(ctx, "1+1")
+	handler.HandleToolEnd(ctx, "2")
+	handler.HandleRetrieverStart(ctx, "query")
+	handler.HandleRetrieverEnd(ctx, "query", []schema.Document{{}, {}})
+	handler.HandleAgentFinish(ctx, schema.AgentFinish{})
+	handler.HandleChainEnd(ctx, nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 5)
+	byName := map[string]tracetest.SpanStub{}
+	for _, span := range spans {
+		byName[span.Name] = span
+	}
+
+	chain := byName["chain"]
+	assert.False(t, chain.Parent.IsValid())
+	for _, name := range []string{"llm", "agent_step", "retriever"} {
+		assert.Equal(t, chain.SpanContext.SpanID(), byName[name].Parent.SpanID(), name)
+	}
+	assert.Equal(t, byName["agent_step"].SpanContext.SpanID(), byName["tool"].Parent.SpanID())
+
+	assert.Contains(t, byName["llm"].Attributes, AttributeModel.String("test-model"))
+	assert.Contains(t, byName["llm"].Attributes, AttributeInputTokens.Int(10))
+	assert.Contains(t, byName["llm"].Attributes, AttributeOutputTokens.Int(4))
+	assert.Contains(t, byName["tool"].Attributes, AttributeToolName.String("calculator"))
+	assert.Contains(t, byName["retriever"].Attributes, AttributeDocuments.Int(2))
+	require.Len(t, chain.Events, 1)
+	assert.Equal(t, "agent_finish", chain.Events[0].Name)
+}
+
+func TestOpenTelemetryHandlerErrors(t *testing.T) {
+	t.Parallel()
+	handler, exporter, _ := newTestOpenTelemetryHandler(t)
+	ctx := context.Background()
+
+	handler.HandleChainStart(ctx, nil)
+	handler.HandleLLMGenerateContentStart(ctx, nil)
+	handler.HandleChainError(ctx, errors.New("boom"))
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+	assert.Equal(t, "llm", spans[0].Name)
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+	assert.Equal(t, "chain", spans[1].Name)
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Equal(t, "boom", spans[1].Status.Description)
+	assert.Empty(t, handler.runs)
+
+	// Ending a run that was not started is ignored.
+	handler.HandleToolEnd(ctx, "")
+	assert.Len(t, exporter.GetSpans(), 2)
+}
+
+func TestOpenTelemetryHandlerMetrics(t *testing.T) {
+	t.Parallel()
+	handler, _, reader := newTestOpenTelemetryHandler(t)
+	ctx := context.Background()
+
+	handler.HandleLLMGenerateContentStart(ctx, nil)
+	handler.HandleLLMGenerateContentEnd(ctx, &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		GenerationInfo: map[string]any{"model": "test-model", "input_tokens": 7, "output_tokens": 3},
+	}}})
+	handler.HandleToolStart(ctx, "")
+	handler.HandleToolError(ctx, errors.New("failed"))
+
+	var data metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &data))
+	require.Len(t, data.ScopeMetrics, 1)
+	metrics := map[string]metricdata.Metrics{}
+	for _, m := range data.ScopeMetrics[0].Metrics {
+		metrics[m.Name] = m
+	}
+
+	tokens := metrics["langchaingo.llm.tokens"].Data.(metricdata.Sum[int64])
+	counts := map[string]int64{}
+	for _, point := range tokens.DataPoints {
+		tokenType, _ := point.Attributes.Value(AttributeTokenType)
+		counts[tokenType.AsString()] = point.Value
+	}
+	assert.Equal(t, map[string]int64{"input": 7, "output": 3}, counts)
+
+	errorCount := metrics["langchaingo.run.errors"].Data.(metricdata.Sum[int64])
+	require.Len(t, errorCount.DataPoints, 1)
+	assert.Equal(t, int64(1), errorCount.DataPoints[0].Value)
+	assert.Equal(t, attribute.NewSet(AttributeRunType.String(RunTypeTool)), errorCount.DataPoints[0].Attributes)
+
+	duration := metrics["langchaingo.run.duration"].Data.(metricdata.Histogram[float64])
+	assert.Len(t, duration.DataPoints, 2)
+}
+
+func TestOpenTelemetryHandlerRuns(t *testing.T) {
+	t.Parallel()
+	handler, exporter, _ := newTestOpenTelemetryHandler(t)
+
+	chainCtx := StartRun(context.Background(), RunTypeChain, "Executor")
+	handler.HandleChainStart(chainCtx, nil)
+	handler.HandleAgentAction(chainCtx, schema.AgentAction{Tool: "search"})
+	toolCtx := StartRun(chainCtx, RunTypeTool, "search")
+	handler.HandleToolStart(toolCtx, "query")
+	llmCtx := StartRun(toolCtx, RunTypeLLM, "openai")
+	handler.HandleLLMGenerateContentStart(llmCtx, nil)
+	handler.HandleLLMGenerateContentEnd(llmCtx, nil)
+	handler.HandleToolEnd(toolCtx, "result")
+	handler.HandleChainEnd(chainCtx, nil)
+
+	byName := map[string]tracetest.SpanStub{}
+	for _, span := range exporter.GetSpans() {
+		byName[span.Name] = span
+	}
+	require.Len(t, byName, 4)
+	chain, step, tool, llm := byName["chain Executor"], byName["agent_step"], byName["tool search"], byName["llm openai"]
+	assert.Equal(t, chain.SpanContext.SpanID(), step.Parent.SpanID())
+	assert.Equal(t, step.SpanContext.SpanID(), tool.Parent.SpanID())
+	assert.Equal(t, tool.SpanContext.SpanID(), llm.Parent.SpanID())
+	assert.Contains(t, tool.Attributes, AttributeToolName.String("search"))
+	assert.Empty(t, handler.runs)
+	assert.Empty(t, handler.runContexts)
+}
+
+func TestOpenTelemetryHandlerAbandonedRuns(t *testing.T) {
+	t.Parallel()
+	handler, exporter, _ := newTestOpenTelemetryHandler(t)
+
+	ctx, cancel := context.WithCancel(StartRun(context.Background(), RunTypeLLM, "openai"))
+	handler.HandleLLMGenerateContentStart(ctx, nil)
+	// The stream is canceled and the end of the LLM call is never reported.
+	cancel()
+
+	require.Eventually(t, func() bool { return len(exporter.GetSpans()) == 1 }, time.Second, time.Millisecond)
+	span := exporter.GetSpans()[0]
+	assert.Equal(t, codes.Error, span.Status.Code)
+	assert.Equal(t, context.Canceled.Error(), span.Status.Description)
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	assert.Empty(t, handler.runs)
+	assert.Empty(t, handler.runContexts)
+	assert.Empty(t, handler.stopAbandon)
+}
@@ -0,0 +1,108 @@
+package callbacks
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Run types, set as the type of the runs started with StartRun and as the
+// "langchaingo.run.type" attribute of the spans and metrics of the
+// OpenTelemetryHandler.
+const (
+	RunTypeChain     = "chain"
+	RunTypeLLM       = "llm"
+	RunTypeTool      = "tool"
+	RunTypeRetriever = "retriever"
+	RunTypeAgentStep = "agent_step"
+)
+
+// RunInfo identifies a run of a chain, LLM, tool or retriever. The callbacks
+// of a run are called with a context carrying its RunInfo, so that handlers
+// can tell runs apart when they nest or run concurrently.
+type RunInfo struct {
+	// ID is the unique ID of the run.
+	ID string `json:"id"`
+	// ParentID is the ID of the run within which the run was started, or an
+	// empty string for a root run.
+	ParentID string `json:"parent_id,omitempty"`
+	// Name is the name of the chain, LLM, tool or retriever.
+	Name string `json:"name"`
+	// Type is the type of the run, one of the RunType constants.
+	Type string `json:"type"`
+	// StartTime is the time the run was started.
+	StartTime time.Time `json:"start_time"`
+}
+
+type runContextKey struct{}
+
+// StartRun returns a copy of ctx carrying a new run with the given type and
+// name, child of the run of ctx, if any. Callers call the start callback of
+// the run, the run itself and its end callback with the returned context.
+func StartRun(ctx context.Context, runType, name string) context.Context {
+	info := RunInfo{
+		ID:        uuid.NewString(),
+		Name:      name,
+		Type:      runType,
+		StartTime: time.Now(),
+	}
+	if parent, ok := RunFromContext(ctx); ok {
+		info.ParentID = parent.ID
+	}
+	return context.WithValue(ctx, runContextKey{}, info)
+}
+
+// RunFromContext returns the run carried by ctx, if any.
+func RunFromContext(ctx context.Context) (RunInfo, bool) {
+	info, ok := ctx.Value(runContextKey{}).(RunInfo)
+	return info, ok
+}
+
+type toolRunHandlerKey struct{}
+
+// toolRunHandler is the handler to which the caller of a tool reports the
+// tool run with the given ID.
+type toolRunHandler struct {
+	runID   string
+	handler Handler
+}
+
+// StartToolRun returns a copy of ctx carrying a new run of the named tool,
+// started with StartRun, whose call is reported to handler by the caller of
+// the tool, such as an agent executor.
+func StartToolRun(ctx context.Context, name string, handler Handler) context.Context {
+	ctx = StartRun(ctx, RunTypeTool, name)
+	info, _ := RunFromContext(ctx)
+	return context.WithValue(ctx, toolRunHandlerKey{}, toolRunHandler{runID: info.ID, handler: handler})
+}
+
+// ToolRunReported reports whether ctx carries a run of the named tool whose
+// call is already reported to handler by the caller of the tool. Tools
+// reporting their own calls skip their callbacks in that case, so that calls
+// are not reported twice to the same handler.
+func ToolRunReported(ctx context.Context, name string, handler Handler) bool {
+	info, ok := RunFromContext(ctx)
+	if !ok || info.Type != RunTypeTool || info.Name != name {
+		return false
+	}
+	reporter, ok := ctx.Value(toolRunHandlerKey{}).(toolRunHandler)
+	return ok && reporter.runID == info.ID && sameHandler(reporter.handler, handler)
+}
+
+// sameHandler reports whether a and b are the same handler. Handlers whose
+// type is not comparable, such as CombiningHandler values, are compared
+// deeply.
+func sameHandler(a, b Handler) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+	if !reflect.TypeOf(a).Comparable() {
+		return reflect.DeepEqual(a, b)
+	}
+	return a == b
+}
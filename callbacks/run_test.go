@@ -0,0 +1,51 @@
+package callbacks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStartRun(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	_, ok := RunFromContext(ctx)
+	assert.False(t, ok)
+
+	chainCtx := StartRun(ctx, RunTypeChain, "LLMChain")
+	chain, ok := RunFromContext(chainCtx)
+	require.True(t, ok)
+	assert.NotEmpty(t, chain.ID)
+	assert.Empty(t, chain.ParentID)
+	assert.Equal(t, "LLMChain", chain.Name)
+	assert.Equal(t, RunTypeChain, chain.Type)
+	assert.False(t, chain.StartTime.IsZero())
+
+	llm, ok := RunFromContext(StartRun(chainCtx, RunTypeLLM, "openai"))
+	require.True(t, ok)
+	assert.NotEqual(t, chain.ID, llm.ID)
+	assert.Equal(t, chain.ID, llm.ParentID)
+}
+
+func TestToolRunReported(t *testing.T) {
+	t.Parallel()
+	handler := NewRunTreeHandler()
+	other := NewRunTreeHandler()
+	combined := CombiningHandler{Callbacks: []Handler{handler, other}}
+
+	ctx := StartToolRun(context.Background(), "calculator", handler)
+	assert.True(t, ToolRunReported(ctx, "calculator", handler))
+	assert.False(t, ToolRunReported(ctx, "calculator", other))
+	assert.False(t, ToolRunReported(ctx, "calculator", nil))
+	assert.False(t, ToolRunReported(ctx, "search", handler))
+
+	// The run of a tool called within the tool run is not reported.
+	assert.False(t, ToolRunReported(StartRun(ctx, RunTypeTool, "calculator"), "calculator", handler))
+	// Combining handlers are not comparable.
+	ctx = StartToolRun(context.Background(), "calculator", combined)
+	assert.True(t, ToolRunReported(ctx, "calculator", combined))
+	assert.False(t, ToolRunReported(ctx, "calculator", handler))
+	assert.False(t, ToolRunReported(StartRun(context.Background(), RunTypeTool, "calculator"), "calculator", handler))
+}
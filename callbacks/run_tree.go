@@ -0,0 +1,280 @@
+package callbacks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Run is a run of a chain, LLM, tool or retriever recorded by a
+// RunTreeHandler, with the runs started within it as children.
+type Run struct {
+	RunInfo
+	EndTime  *time.Time     `json:"end_time,omitempty"`
+	Inputs   map[string]any `json:"inputs,omitempty"`
+	Outputs  map[string]any `json:"outputs,omitempty"`
+	Error    string         `json:"error,omitempty"`
+	Events   []RunEvent     `json:"events,omitempty"`
+	Children []*Run         `json:"children,omitempty"`
+}
+
+// RunEvent is an event that happened during a run, such as an action of an
+// agent.
+type RunEvent struct {
+	Name string         `json:"name"`
+	Time time.Time      `json:"time"`
+	Data map[string]any `json:"data,omitempty"`
+}
+
+// RunTreeHandler is a callback handler that records the tree of the runs of
+// chains, LLMs, tools and retrievers, with their inputs, outputs, errors and
+// timing, for debugging and offline evaluation.
+//
+// Runs are identified by the RunInfo of the context of their callbacks. Runs
+// whose callers do not start a RunInfo are matched by context instead, and
+// are recorded as children of the innermost run open with the same context.
+type RunTreeHandler struct {
+	SimpleHandler
+
+	mu      sync.Mutex
+	runs    map[string]*Run
+	roots   []*Run
+	pending map[context.Context][]*Run
+	// stopAbandon unregisters, by context, the function ending the pending
+	// runs when the context is done.
+	stopAbandon map[context.Context]func() bool
+}
+
+var _ Handler = &RunTreeHandler{}
+
+// NewRunTreeHandler creates a new run tree handler.
+func NewRunTreeHandler() *RunTreeHandler {
+	return &RunTreeHandler{
+		runs:        map[string]*Run{},
+		pending:     map[context.Context][]*Run{},
+		stopAbandon: map[context.Context]func() bool{},
+	}
+}
+
+// Runs returns a copy of the root runs recorded so far, in the order they were
+// started.
+func (h *RunTreeHandler) Runs() []*Run {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	runs := make([]*Run, len(h.roots))
+	for i, run := range h.roots {
+		runs[i] = copyRun(run)
+	}
+	return runs
+}
+
+// WriteJSON writes the runs recorded so far to w as an indented JSON array.
+func (h *RunTreeHandler) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(h.Runs())
+}
+
+// Reset removes the runs recorded so far, including the runs that have not
+// ended yet.
+func (h *RunTreeHandler) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, stop := range h.stopAbandon {
+		stop()
+	}
+	h.runs = map[string]*Run{}
+	h.roots = nil
+	h.pending = map[context.Context][]*Run{}
+	h.stopAbandon = map[context.Context]func() bool{}
+}
+
+func (h *RunTreeHandler) HandleText(ctx context.Context, text string) {
+	h.event(ctx, "text", map[string]any{"text": text})
+}
+
+func (h *RunTreeHandler) HandleLLMGenerateContentStart(ctx context.Context, ms []llms.MessageContent) {
+	h.start(ctx, RunTypeLLM, map[string]any{"messages": ms})
+}
+
+func (h *RunTreeHandler) HandleLLMGenerateContentEnd(ctx context.Context, res *llms.ContentResponse) {
+	var outputs map[string]any
+	if res != nil {
+		outputs = map[string]any{"choices": res.Choices}
+	}
+	h.end(ctx, RunTypeLLM, outputs, nil)
+}
+
+func (h *RunTreeHandler) HandleLLMError(ctx context.Context, err error) {
+	h.end(ctx, RunTypeLLM, nil, err)
+}
+
+func (h *RunTreeHandler) HandleChainStart(ctx context.Context, inputs map[string]any) {
+	h.start(ctx, RunTypeChain, inputs)
+}
+
+func (h *RunTreeHandler) HandleChainEnd(ctx context.Context, outputs map[string]any) {
+	h.end(ctx, RunTypeChain, outputs, nil)
+}
+
+func (h *RunTreeHandler) HandleChainError(ctx context.Context, err error) {
+	h.end(ctx, RunTypeChain, nil, err)
+}
+
+func (h *RunTreeHandler) HandleToolStart(ctx context.Context, input string) {
+	h.start(ctx, RunTypeTool, map[string]any{"input": input})
+}
+
+func (h *RunTreeHandler) HandleToolEnd(ctx context.Context, output string) {
+	h.end(ctx, RunTypeTool, map[string]any{"output": output}, nil)
+}
+
+func (h *RunTreeHandler) HandleToolError(ctx context.Context, err error) {
+	h.end(ctx, RunTypeTool, nil, err)
+}
+
+func (h *RunTreeHandler) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	h.event(ctx, "agent_action", map[string]any{"tool": action.Tool, "tool_input": action.ToolInput, "log": action.Log})
+}
+
+func (h *RunTreeHandler) HandleAgentFinish(ctx context.Context, finish schema.AgentFinish) {
+	h.event(ctx, "agent_finish", map[string]any{"return_values": finish.ReturnValues, "log": finish.Log})
+}
+
+func (h *RunTreeHandler) HandleRetrieverStart(ctx context.Context, query string) {
+	h.start(ctx, RunTypeRetriever, map[string]any{"query": query})
+}
+
+func (h *RunTreeHandler) HandleRetrieverEnd(ctx context.Context, _ string, documents []schema.Document) {
+	h.end(ctx, RunTypeRetriever, map[string]any{"documents": documents}, nil)
+}
+
+// start records the start of a run of the given type.
+func (h *RunTreeHandler) start(ctx context.Context, runType string, inputs map[string]any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	info, ok := RunFromContext(ctx)
+	started := ok && info.Type == runType
+	if !started {
+		// The caller did not start a run: create one, child of the innermost
+		// run open with ctx, or of the run of ctx.
+		parentID := info.ID
+		if pending := h.pending[ctx]; len(pending) > 0 {
+			parentID = pending[len(pending)-1].ID
+		}
+		info = RunInfo{ID: uuid.NewString(), ParentID: parentID, Name: runType, Type: runType, StartTime: time.Now()}
+	}
+
+	run := &Run{RunInfo: info, Inputs: inputs}
+	h.runs[info.ID] = run
+	if !started {
+		if len(h.pending[ctx]) == 0 {
+			// Runs whose end is never reported are ended when ctx is done,
+			// so that ctx is not kept forever.
+			h.stopAbandon[ctx] = context.AfterFunc(ctx, func() { h.abandon(ctx) })
+		}
+		h.pending[ctx] = append(h.pending[ctx], run)
+	}
+	if parent, ok := h.runs[info.ParentID]; ok {
+		parent.Children = append(parent.Children, run)
+	} else {
+		h.roots = append(h.roots, run)
+	}
+}
+
+// end records the end of the run of the given type of ctx.
+func (h *RunTreeHandler) end(ctx context.Context, runType string, outputs map[string]any, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var run *Run
+	if info, ok := RunFromContext(ctx); ok && info.Type == runType {
+		run = h.runs[info.ID]
+	} else {
+		run = h.popPending(ctx, runType)
+	}
+	if run == nil {
+		return
+	}
+
+	now := time.Now()
+	run.EndTime = &now
+	run.Outputs = outputs
+	if err != nil {
+		run.Error = err.Error()
+	}
+}
+
+// popPending removes and returns the innermost run of the given type started
+// without a RunInfo with ctx.
+func (h *RunTreeHandler) popPending(ctx context.Context, runType string) *Run {
+	pending := h.pending[ctx]
+	for i := len(pending) - 1; i >= 0; i-- {
+		if pending[i].Type != runType {
+			continue
+		}
+		run := pending[i]
+		pending = append(pending[:i:i], pending[i+1:]...)
+		if len(pending) == 0 {
+			delete(h.pending, ctx)
+			h.stopAbandon[ctx]()
+			delete(h.stopAbandon, ctx)
+		} else {
+			h.pending[ctx] = pending
+		}
+		return run
+	}
+	return nil
+}
+
+// abandon ends the runs still pending with ctx once it is done, with the cause
+// of ctx as error.
+func (h *RunTreeHandler) abandon(ctx context.Context) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	for _, run := range h.pending[ctx] {
+		run.EndTime = &now
+		run.Error = context.Cause(ctx).Error()
+	}
+	delete(h.pending, ctx)
+	delete(h.stopAbandon, ctx)
+}
+
+// event records an event in the innermost run of ctx.
+func (h *RunTreeHandler) event(ctx context.Context, name string, data map[string]any) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var run *Run
+	if pending := h.pending[ctx]; len(pending) > 0 {
+		run = pending[len(pending)-1]
+	} else if info, ok := RunFromContext(ctx); ok {
+		run = h.runs[info.ID]
+	}
+	if run == nil {
+		return
+	}
+	run.Events = append(run.Events, RunEvent{Name: name, Time: time.Now(), Data: data})
+}
+
+func copyRun(run *Run) *Run {
+	c := *run
+	c.Events = append([]RunEvent(nil), run.Events...)
+	c.Children = make([]*Run, len(run.Children))
+	for i, child := range run.Children {
+		c.Children[i] = copyRun(child)
+	}
+	if len(c.Children) == 0 {
+		c.Children = nil
+	}
+	return &c
+}
@@ -0,0 +1,145 @@
+package callbacks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+func TestRunTreeHandler(t *testing.T) {
+	t.Parallel()
+	handler := NewRunTreeHandler()
+
+	chainCtx := StartRun(context.Background(), RunTypeChain, "Executor")
+	handler.HandleChainStart(chainCtx, map[string]any{"input": "question"})
+
+	llmCtx := StartRun(chainCtx, RunTypeLLM, "openai")
+	handler.HandleLLMGenerateContentStart(llmCtx, nil)
+	handler.HandleLLMGenerateContentEnd(llmCtx, &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{{Content: "answer"}},
+	})
+	handler.HandleAgentAction(chainCtx, schema.AgentAction{Tool: "search", ToolInput: "query"})
+
+	// Tools running concurrently are told apart by their run.
+	var wg sync.WaitGroup
+	for _, name := range []string{"search", "calculator"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			toolCtx := StartRun(chainCtx, RunTypeTool, name)
+			handler.HandleToolStart(toolCtx, name+" input")
+			handler.HandleToolEnd(toolCtx, name+" output")
+		}(name)
+	}
+	wg.Wait()
+	handler.HandleChainError(chainCtx, errors.New("failed"))
+
+	runs := handler.Runs()
+	require.Len(t, runs, 1)
+	chain := runs[0]
+	assert.Equal(t, "Executor", chain.Name)
+	assert.Equal(t, map[string]any{"input": "question"}, chain.Inputs)
+	assert.Equal(t, "failed", chain.Error)
+	assert.NotNil(t, chain.EndTime)
+	require.Len(t, chain.Events, 1)
+	assert.Equal(t, "agent_action", chain.Events[0].Name)
+
+	require.Len(t, chain.Children, 3)
+	assert.Equal(t, RunTypeLLM, chain.Children[0].Type)
+	assert.Equal(t, chain.ID, chain.Children[0].ParentID)
+	for _, tool := range chain.Children[1:] {
+		assert.Equal(t, RunTypeTool, tool.Type)
+		assert.Equal(t, map[string]any{"input": tool.Name + " input"}, tool.Inputs)
+		assert.Equal(t, map[string]any{"output": tool.Name + " output"}, tool.Outputs)
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, handler.WriteJSON(&buf))
+	var exported []map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &exported))
+	require.Len(t, exported, 1)
+	assert.Equal(t, chain.ID, exported[0]["id"])
+	assert.Len(t, exported[0]["children"], 3)
+
+	handler.Reset()
+	assert.Empty(t, handler.Runs())
+}
+
+func TestRunTreeHandlerWithoutRuns(t *testing.T) {
+	t.Parallel()
+	handler := NewRunTreeHandler()
+	ctx := context.Background()
+
+	// Callers that do not start runs are matched by context.
+	handler.HandleChainStart(ctx, nil)
+	handler.HandleRetrieverStart(ctx, "query")
+	handler.HandleRetrieverEnd(ctx, "query", []schema.Document{{PageContent: "doc"}})
+	handler.HandleChainEnd(ctx, map[string]any{"output": "done"})
+
+	runs := handler.Runs()
+	require.Len(t, runs, 1)
+	assert.Equal(t, RunTypeChain, runs[0].Type)
+	assert.Equal(t, map[string]any{"output": "done"}, runs[0].Outputs)
+	require.Len(t, runs[0].Children, 1)
+	retriever := runs[0].Children[0]
+	assert.Equal(t, RunTypeRetriever, retriever.Type)
+	assert.Equal(t, runs[0].ID, retriever.ParentID)
+	assert.NotNil(t, retriever.EndTime)
+	assert.Empty(t, handler.pending)
+}
+
+func TestRunTreeHandlerAbandonedRuns(t *testing.T) {
+	t.Parallel()
+	handler := NewRunTreeHandler()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handler.HandleLLMGenerateContentStart(ctx, nil)
+	// The stream is canceled and the end of the LLM call is never reported.
+	cancel()
+
+	require.Eventually(t, func() bool {
+		handler.mu.Lock()
+		defer handler.mu.Unlock()
+		return len(handler.pending) == 0
+	}, time.Second, time.Millisecond)
+	runs := handler.Runs()
+	require.Len(t, runs, 1)
+	assert.NotNil(t, runs[0].EndTime)
+	assert.Equal(t, context.Canceled.Error(), runs[0].Error)
+	assert.Empty(t, handler.stopAbandon)
+
+	// Runs that end normally do not keep their context either.
+	handler.HandleChainStart(context.Background(), nil)
+	handler.HandleChainEnd(context.Background(), nil)
+	assert.Empty(t, handler.stopAbandon)
+}
+
+func TestRunTreeHandlerResetPendingRuns(t *testing.T) {
+	t.Parallel()
+	handler := NewRunTreeHandler()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handler.HandleChainStart(ctx, nil)
+	handler.Reset()
+	assert.Empty(t, handler.stopAbandon)
+
+	// The run removed by the reset is not ended when its context is canceled.
+	ended := make(chan struct{})
+	context.AfterFunc(ctx, func() { close(ended) })
+	cancel()
+	<-ended
+	handler.mu.Lock()
+	defer handler.mu.Unlock()
+	assert.Empty(t, handler.runs)
+	assert.Empty(t, handler.pending)
+}
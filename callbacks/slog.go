@@ -0,0 +1,330 @@
+package callbacks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// _defaultSlogMaxLength is the default maximum length, in runes, of the text
+// values logged by the SlogHandler.
+const _defaultSlogMaxLength = 1000
+
+// Events logged by the SlogHandler, used as the messages of its records.
+const (
+	SlogEventText           = "text"
+	SlogEventLLMStart       = "llm.start"
+	SlogEventLLMEnd         = "llm.end"
+	SlogEventLLMError       = "llm.error"
+	SlogEventLLMStream      = "llm.stream"
+	SlogEventChainStart     = "chain.start"
+	SlogEventChainEnd       = "chain.end"
+	SlogEventChainError     = "chain.error"
+	SlogEventToolStart      = "tool.start"
+	SlogEventToolEnd        = "tool.end"
+	SlogEventToolError      = "tool.error"
+	SlogEventAgentAction    = "agent.action"
+	SlogEventAgentFinish    = "agent.finish"
+	SlogEventRetrieverStart = "retriever.start"
+	SlogEventRetrieverEnd   = "retriever.end"
+)
+
+// Redactor rewrites a text before it is logged, to remove personal data or
+// secrets.
+type Redactor func(text string) string
+
+// NewRegexpRedactor returns a Redactor replacing the matches of the patterns
+// with replacement.
+func NewRegexpRedactor(replacement string, patterns ...*regexp.Regexp) Redactor {
+	return func(text string) string {
+		for _, pattern := range patterns {
+			text = pattern.ReplaceAllString(text, replacement)
+		}
+		return text
+	}
+}
+
+// DefaultRedactor redacts email addresses, payment card numbers, bearer tokens
+// and common API keys.
+var DefaultRedactor = NewRegexpRedactor("[REDACTED]", //nolint:gochecknoglobals
+	regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),
+	regexp.MustCompile(`\b(?:\d[ \-]?){13,16}\b`),
+	regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`),
+	regexp.MustCompile(`\b(?:sk|pk|rk)-[A-Za-z0-9\-_]{16,}\b`),
+	regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),
+	regexp.MustCompile(`\bAIza[0-9A-Za-z\-_]{35}\b`),
+)
+
+// SlogHandler is a callback handler that logs structured records with a
+// slog.Logger. Every callback is logged as a record whose message is one of
+// the SlogEvent constants, with the run of the callback, if any, in a "run"
+// group. Prompts, outputs, tool inputs and other texts are redacted and
+// truncated before being logged.
+type SlogHandler struct {
+	logger    *slog.Logger
+	levels    map[string]slog.Level
+	maxLength int
+	redactors []Redactor
+}
+
+var _ Handler = &SlogHandler{}
+
+// SlogOption is an option for the SlogHandler.
+type SlogOption func(h *SlogHandler)
+
+// WithLogger sets the logger of the handler. The default is slog.Default().
+func WithLogger(logger *slog.Logger) SlogOption {
+	return func(h *SlogHandler) {
+		h.logger = logger
+	}
+}
+
+// WithEventLevel sets the level of the records of an event. By default, errors
+// are logged at the error level, texts and streamed chunks at the debug level
+// and other events at the info level.
+func WithEventLevel(event string, level slog.Level) SlogOption {
+	return func(h *SlogHandler) {
+		h.levels[event] = level
+	}
+}
+
+// WithMaxLength sets the maximum length, in runes, of the logged texts, longer
+// texts being truncated. The default is 1000; zero or less disables
+// truncation.
+func WithMaxLength(n int) SlogOption {
+	return func(h *SlogHandler) {
+		h.maxLength = n
+	}
+}
+
+// WithRedactors adds redactors applied, in order, to the logged texts. By
+// default, texts are not redacted; DefaultRedactor covers common personal data
+// and secrets.
+func WithRedactors(redactors ...Redactor) SlogOption {
+	return func(h *SlogHandler) {
+		h.redactors = append(h.redactors, redactors...)
+	}
+}
+
+// NewSlogHandler creates a new slog callback handler.
+func NewSlogHandler(opts ...SlogOption) *SlogHandler {
+	h := &SlogHandler{
+		logger: slog.Default(),
+		levels: map[string]slog.Level{
+			SlogEventText:       slog.LevelDebug,
+			SlogEventLLMStream:  slog.LevelDebug,
+			SlogEventLLMError:   slog.LevelError,
+			SlogEventChainError: slog.LevelError,
+			SlogEventToolError:  slog.LevelError,
+		},
+		maxLength: _defaultSlogMaxLength,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+func (h *SlogHandler) HandleText(ctx context.Context, text string) {
+	h.log(ctx, SlogEventText, func() []slog.Attr {
+		return []slog.Attr{h.text("text", text)}
+	})
+}
+
+func (h *SlogHandler) HandleLLMStart(ctx context.Context, prompts []string) {
+	h.log(ctx, SlogEventLLMStart, func() []slog.Attr {
+		attrs := make([]slog.Attr, len(prompts))
+		for i, prompt := range prompts {
+			attrs[i] = h.text(fmt.Sprint(i), prompt)
+		}
+		return []slog.Attr{{Key: "prompts", Value: slog.GroupValue(attrs...)}}
+	})
+}
+
+func (h *SlogHandler) HandleLLMGenerateContentStart(ctx context.Context, ms []llms.MessageContent) {
+	h.log(ctx, SlogEventLLMStart, func() []slog.Attr {
+		attrs := make([]slog.Attr, len(ms))
+		for i, m := range ms {
+			var text strings.Builder
+			for _, part := range m.Parts {
+				if part, ok := part.(llms.TextContent); ok {
+					text.WriteString(part.Text)
+				}
+			}
+			attrs[i] = slog.Group(fmt.Sprint(i), slog.String("role", string(m.Role)), h.text("text", text.String()))
+		}
+		return []slog.Attr{{Key: "messages", Value: slog.GroupValue(attrs...)}}
+	})
+}
+
+func (h *SlogHandler) HandleLLMGenerateContentEnd(ctx context.Context, res *llms.ContentResponse) {
+	h.log(ctx, SlogEventLLMEnd, func() []slog.Attr {
+		var attrs []slog.Attr
+		if model := responseModel(res); model != "" {
+			attrs = append(attrs, slog.String("model", model))
+		}
+		if usage := responseTokenUsage(res); usage.found {
+			attrs = append(attrs, slog.Group("usage",
+				slog.Int("input_tokens", usage.input), slog.Int("output_tokens", usage.output)))
+		}
+		if res == nil {
+			return attrs
+		}
+		choices := make([]slog.Attr, 0, len(res.Choices))
+		for i, choice := range res.Choices {
+			if choice == nil {
+				continue
+			}
+			choiceAttrs := []slog.Attr{h.text("content", choice.Content)}
+			if choice.StopReason != "" {
+				choiceAttrs = append(choiceAttrs, slog.String("stop_reason", choice.StopReason))
+			}
+			for _, call := range choice.ToolCalls {
+				if call.FunctionCall != nil {
+					choiceAttrs = append(choiceAttrs, slog.Group("tool_call",
+						slog.String("name", call.FunctionCall.Name),
+						h.text("arguments", call.FunctionCall.Arguments)))
+				}
+			}
+			choices = append(choices, slog.Attr{Key: fmt.Sprint(i), Value: slog.GroupValue(choiceAttrs...)})
+		}
+		return append(attrs, slog.Attr{Key: "choices", Value: slog.GroupValue(choices...)})
+	})
+}
+
+func (h *SlogHandler) HandleLLMError(ctx context.Context, err error) {
+	h.logError(ctx, SlogEventLLMError, err)
+}
+
+func (h *SlogHandler) HandleChainStart(ctx context.Context, inputs map[string]any) {
+	h.log(ctx, SlogEventChainStart, func() []slog.Attr {
+		return []slog.Attr{h.values("inputs", inputs)}
+	})
+}
+
+func (h *SlogHandler) HandleChainEnd(ctx context.Context, outputs map[string]any) {
+	h.log(ctx, SlogEventChainEnd, func() []slog.Attr {
+		return []slog.Attr{h.values("outputs", outputs)}
+	})
+}
+
+func (h *SlogHandler) HandleChainError(ctx context.Context, err error) {
+	h.logError(ctx, SlogEventChainError, err)
+}
+
+func (h *SlogHandler) HandleToolStart(ctx context.Context, input string) {
+	h.log(ctx, SlogEventToolStart, func() []slog.Attr {
+		return []slog.Attr{h.text("input", input)}
+	})
+}
+
+func (h *SlogHandler) HandleToolEnd(ctx context.Context, output string) {
+	h.log(ctx, SlogEventToolEnd, func() []slog.Attr {
+		return []slog.Attr{h.text("output", output)}
+	})
+}
+
+func (h *SlogHandler) HandleToolError(ctx context.Context, err error) {
+	h.logError(ctx, SlogEventToolError, err)
+}
+
+func (h *SlogHandler) HandleAgentAction(ctx context.Context, action schema.AgentAction) {
+	h.log(ctx, SlogEventAgentAction, func() []slog.Attr {
+		return []slog.Attr{
+			slog.String("tool", action.Tool),
+			h.text("tool_input", action.ToolInput),
+			h.text("log", action.Log),
+		}
+	})
+}
+
+func (h *SlogHandler) HandleAgentFinish(ctx context.Context, finish schema.AgentFinish) {
+	h.log(ctx, SlogEventAgentFinish, func() []slog.Attr {
+		return []slog.Attr{h.values("return_values", finish.ReturnValues), h.text("log", finish.Log)}
+	})
+}
+
+func (h *SlogHandler) HandleRetrieverStart(ctx context.Context, query string) {
+	h.log(ctx, SlogEventRetrieverStart, func() []slog.Attr {
+		return []slog.Attr{h.text("query", query)}
+	})
+}
+
+func (h *SlogHandler) HandleRetrieverEnd(ctx context.Context, query string, documents []schema.Document) {
+	h.log(ctx, SlogEventRetrieverEnd, func() []slog.Attr {
+		return []slog.Attr{h.text("query", query), slog.Int("documents", len(documents))}
+	})
+}
+
+func (h *SlogHandler) HandleStreamingFunc(ctx context.Context, chunk []byte) {
+	h.log(ctx, SlogEventLLMStream, func() []slog.Attr {
+		return []slog.Attr{h.text("chunk", string(chunk))}
+	})
+}
+
+// log logs a record for an event, if its level is enabled. The attributes of
+// the record are only computed then.
+func (h *SlogHandler) log(ctx context.Context, event string, attrs func() []slog.Attr) {
+	level := h.level(event)
+	if !h.logger.Enabled(ctx, level) {
+		return
+	}
+	recordAttrs := attrs()
+	if run, ok := RunFromContext(ctx); ok {
+		runAttrs := []slog.Attr{slog.String("id", run.ID), slog.String("type", run.Type)}
+		if run.ParentID != "" {
+			runAttrs = append(runAttrs, slog.String("parent_id", run.ParentID))
+		}
+		if run.Name != "" {
+			runAttrs = append(runAttrs, slog.String("name", run.Name))
+		}
+		recordAttrs = append(recordAttrs, slog.Attr{Key: "run", Value: slog.GroupValue(runAttrs...)})
+	}
+	h.logger.LogAttrs(ctx, level, event, recordAttrs...)
+}
+
+func (h *SlogHandler) logError(ctx context.Context, event string, err error) {
+	h.log(ctx, event, func() []slog.Attr {
+		return []slog.Attr{h.text("error", fmt.Sprint(err))}
+	})
+}
+
+func (h *SlogHandler) level(event string) slog.Level {
+	if level, ok := h.levels[event]; ok {
+		return level
+	}
+	return slog.LevelInfo
+}
+
+// text returns an attribute with a redacted and truncated text.
+func (h *SlogHandler) text(key, text string) slog.Attr {
+	for _, redact := range h.redactors {
+		text = redact(text)
+	}
+	if h.maxLength > 0 {
+		if runes := []rune(text); len(runes) > h.maxLength {
+			text = fmt.Sprintf("%s... (%d more characters)", string(runes[:h.maxLength]), len(runes)-h.maxLength)
+		}
+	}
+	return slog.String(key, text)
+}
+
+// values returns a group of the redacted and truncated values of a map, in the
+// order of their keys.
+func (h *SlogHandler) values(key string, values map[string]any) slog.Attr {
+	keys := maps.Keys(values)
+	slices.Sort(keys)
+	attrs := make([]slog.Attr, len(keys))
+	for i, k := range keys {
+		attrs[i] = h.text(k, fmt.Sprint(values[k]))
+	}
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}
+}
@@ -8,10 +8,10 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"regexp"
 
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/memory"
+	"github.com/tmc/langchaingo/outputparser"
 	"github.com/tmc/langchaingo/prompts"
 	"github.com/tmc/langchaingo/schema"
 )
@@ -71,11 +71,7 @@ func (a APIChain) Call(ctx context.Context, values map[string]any, opts ...Chain
 		return nil, fmt.Errorf("%w: %w", ErrInvalidInputValues, ErrInputValuesWrongType)
 	}
 
-	// Extract the json from llm output
-	re := regexp.MustCompile(`(?s)\{.*\}`)
-	jsonString := re.FindString(outputText)
-
-	// Convert the LLM output into the anonymous struct.
+	// Extract the json from llm output and convert it into the anonymous struct.
 	var output struct {
 		Method  string            `json:"method"`
 		Headers map[string]string `json:"headers"`
@@ -83,7 +79,7 @@ func (a APIChain) Call(ctx context.Context, values map[string]any, opts ...Chain
 		Body    map[string]string `json:"body"`
 	}
 
-	err = json.Unmarshal([]byte(jsonString), &output)
+	err = outputparser.ParseJSON(outputText, &output)
 	if err != nil {
 		return nil, err
 	}
@@ -3,6 +3,7 @@ package chains
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"sync"
 
 	"github.com/tmc/langchaingo/callbacks"
@@ -42,6 +43,7 @@ func Call(ctx context.Context, c Chain, inputValues map[string]any, options ...C
 		fullValues[key] = value
 	}
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunTypeChain, chainName(c))
 	callbacksHandler := getChainCallbackHandler(c)
 	if callbacksHandler != nil {
 		callbacksHandler.HandleChainStart(ctx, inputValues)
@@ -252,6 +254,16 @@ func validateOutputs(c Chain, outputValues map[string]any) error {
 	return nil
 }
 
+// chainName returns the name of the type of a chain, used as the name of its
+// runs.
+func chainName(c Chain) string {
+	t := reflect.TypeOf(c)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
 func getChainCallbackHandler(c Chain) callbacks.Handler {
 	if handlerHaver, ok := c.(callbacks.HandlerHaver); ok {
 		return handlerHaver.GetCallbackHandler()
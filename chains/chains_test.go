@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/prompts"
 )
@@ -113,3 +114,20 @@ func TestApplyWithCanceledContext(t *testing.T) {
 		t.Fatal("expected context canceled error, got:", applyErr)
 	}
 }
+
+func TestCallStartsRun(t *testing.T) {
+	t.Parallel()
+
+	handler := callbacks.NewRunTreeHandler()
+	c := NewLLMChain(&testLanguageModel{expResult: "result"}, prompts.NewPromptTemplate("{{.text}}", []string{"text"}))
+	c.CallbacksHandler = handler
+	_, err := Call(context.Background(), c, map[string]any{"text": "input"})
+	require.NoError(t, err)
+
+	runs := handler.Runs()
+	require.Len(t, runs, 1)
+	require.Equal(t, callbacks.RunTypeChain, runs[0].Type)
+	require.Equal(t, "LLMChain", runs[0].Name)
+	require.Equal(t, map[string]any{"text": "input"}, runs[0].Inputs)
+	require.NotNil(t, runs[0].EndTime)
+}
@@ -20,6 +20,10 @@ type LLMChain struct {
 	CallbacksHandler callbacks.Handler
 	OutputParser     schema.OutputParser[any]
 
+	// OutputParserRetries is the number of times the LLM is asked to try again
+	// when the output parser fails. Parsing is not retried if it is zero.
+	OutputParserRetries int
+
 	OutputKey string
 }
 
@@ -41,6 +45,8 @@ func NewLLMChain(llm llms.Model, prompt prompts.FormatPrompter, opts ...ChainCal
 		Memory:           memory.NewSimple(),
 		OutputKey:        _llmChainDefaultOutputKey,
 		CallbacksHandler: opt.CallbackHandler,
+
+		OutputParserRetries: opt.OutputParserRetries,
 	}
 
 	return chain
@@ -61,7 +67,7 @@ func (c LLMChain) Call(ctx context.Context, values map[string]any, options ...Ch
 		return nil, err
 	}
 
-	finalOutput, err := c.OutputParser.ParseWithPrompt(result, promptValue)
+	finalOutput, err := c.parse(ctx, result, promptValue, options...)
 	if err != nil {
 		return nil, err
 	}
@@ -69,6 +75,22 @@ func (c LLMChain) Call(ctx context.Context, values map[string]any, options ...Ch
 	return map[string]any{c.OutputKey: finalOutput}, nil
 }
 
+// parse parses the output of the llm, asking the llm to try again if the output
+// parser fails and OutputParserRetries is set. Parsers that call a model are
+// given the context and the LLM call options of the chain.
+func (c LLMChain) parse(
+	ctx context.Context, result string, promptValue llms.PromptValue, options ...ChainCallOption,
+) (any, error) {
+	var parser schema.OutputParser[any] = c.OutputParser
+	if c.OutputParserRetries > 0 {
+		parser = outputparser.NewRetry(c.OutputParser, c.LLM, c.OutputParserRetries)
+	}
+	if p, ok := parser.(outputparser.ContextParser[any]); ok {
+		return p.ParseWithPromptContext(ctx, result, promptValue, getLLMCallOptions(options...)...)
+	}
+	return parser.ParseWithPrompt(result, promptValue)
+}
+
 // GetMemory returns the memory.
 func (c LLMChain) GetMemory() schema.Memory { //nolint:ireturn
 	return c.Memory //nolint:ireturn
@@ -8,8 +8,11 @@ import (
 
 	"github.com/stretchr/testify/require"
 	"github.com/tmc/langchaingo/callbacks"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
 	"github.com/tmc/langchaingo/llms/googleai"
 	"github.com/tmc/langchaingo/llms/openai"
+	"github.com/tmc/langchaingo/outputparser"
 	"github.com/tmc/langchaingo/prompts"
 )
 
@@ -88,3 +91,57 @@ func TestLLMChainWithGoogleAI(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, strings.Contains(result, "Paris"))
 }
+
+func TestLLMChainOutputParserRetries(t *testing.T) {
+	t.Parallel()
+
+	model := fake.NewFakeLLM([]string{"maybe", "yes"})
+	prompt := prompts.NewPromptTemplate("Is {{.city}} in France?", []string{"city"})
+
+	chain := NewLLMChain(model, prompt)
+	chain.OutputParser = outputparser.NewBooleanParser()
+	_, err := Call(context.Background(), chain, map[string]any{"city": "Paris"})
+	require.Error(t, err)
+
+	model.Reset()
+	chain = NewLLMChain(model, prompt, WithOutputParserRetries(1))
+	chain.OutputParser = outputparser.NewBooleanParser()
+	result, err := Call(context.Background(), chain, map[string]any{"city": "Paris"})
+	require.NoError(t, err)
+	require.Equal(t, true, result[chain.OutputKey])
+}
+
+// modelRecordingLLM is a fake LLM recording the model option of its calls.
+type modelRecordingLLM struct {
+	*fake.LLM
+
+	models []string
+}
+
+func (l *modelRecordingLLM) GenerateContent(
+	ctx context.Context,
+	messages []llms.MessageContent,
+	options ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	opts := llms.CallOptions{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	l.models = append(l.models, opts.Model)
+	return l.LLM.GenerateContent(ctx, messages, options...)
+}
+
+func TestLLMChainOutputParserRetriesCallOptions(t *testing.T) {
+	t.Parallel()
+
+	model := &modelRecordingLLM{LLM: fake.NewFakeLLM([]string{"maybe", "yes"})}
+	prompt := prompts.NewPromptTemplate("Is {{.city}} in France?", []string{"city"})
+	chain := NewLLMChain(model, prompt, WithOutputParserRetries(1))
+	chain.OutputParser = outputparser.NewBooleanParser()
+
+	// The LLM is asked to try again with the call options of the chain.
+	result, err := Call(context.Background(), chain, map[string]any{"city": "Paris"}, WithModel("small"))
+	require.NoError(t, err)
+	require.Equal(t, true, result[chain.OutputKey])
+	require.Equal(t, []string{"small", "small"}, model.models)
+}
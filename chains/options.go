@@ -65,6 +65,10 @@ type chainCallOption struct {
 
 	// CallbackHandler is the callback handler for Chain
 	CallbackHandler callbacks.Handler
+
+	// OutputParserRetries is the number of times the LLM is asked to try again
+	// when the output parser of an LLMChain fails.
+	OutputParserRetries int
 }
 
 // WithModel is an option for LLM.Call.
@@ -161,6 +165,14 @@ func WithCallback(callbackHandler callbacks.Handler) ChainCallOption {
 	}
 }
 
+// WithOutputParserRetries is an option for NewLLMChain that makes the chain ask
+// the LLM to try again, up to maxRetries times, when the output parser fails.
+func WithOutputParserRetries(maxRetries int) ChainCallOption {
+	return func(o *chainCallOption) {
+		o.OutputParserRetries = maxRetries
+	}
+}
+
 func getLLMCallOptions(options ...ChainCallOption) []llms.CallOption { //nolint:cyclop
 	opts := &chainCallOption{}
 	for _, option := range options {
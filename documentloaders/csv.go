@@ -19,7 +19,7 @@ type CSV struct {
 	columns []string
 }
 
-var _ Loader = CSV{}
+var _ LazyLoader = CSV{}
 
 // NewCSV creates a new csv loader with an io.Reader and optional column names for filtering.
 func NewCSV(r io.Reader, columns ...string) CSV {
@@ -29,45 +29,52 @@ func NewCSV(r io.Reader, columns ...string) CSV {
 	}
 }
 
-// Load reads from the io.Reader and returns a single document with the data.
-func (c CSV) Load(_ context.Context) ([]schema.Document, error) {
-	var header []string
-	var docs []schema.Document
-	var rown int
+// Load reads from the io.Reader and returns a document for every row.
+func (c CSV) Load(ctx context.Context) ([]schema.Document, error) {
+	return collectDocuments(ctx, c.LazyLoad(ctx))
+}
 
-	rd := csv.NewReader(c.r)
-	for {
-		row, err := rd.Read()
-		if errors.Is(err, io.EOF) {
-			break
-		}
-		if err != nil {
-			return nil, err
-		}
-		if len(header) == 0 {
-			header = append(header, row...)
-			continue
-		}
+// LazyLoad reads from the io.Reader and sends a document for every row as it is
+// read.
+func (c CSV) LazyLoad(ctx context.Context) <-chan DocumentResult {
+	return lazyLoad(ctx, func(yield func(schema.Document) bool) error {
+		var header []string
+		var rown int
 
-		var content []string
-		for i, value := range row {
-			if len(c.columns) > 0 &&
-				!slices.Contains(c.columns, header[i]) {
+		rd := csv.NewReader(c.r)
+		for {
+			row, err := rd.Read()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if len(header) == 0 {
+				header = append(header, row...)
 				continue
 			}
 
-			line := fmt.Sprintf("%s: %s", header[i], value)
-			content = append(content, line)
-		}
+			var content []string
+			for i, value := range row {
+				if len(c.columns) > 0 &&
+					!slices.Contains(c.columns, header[i]) {
+					continue
+				}
 
-		rown++
-		docs = append(docs, schema.Document{
-			PageContent: strings.Join(content, "\n"),
-			Metadata:    map[string]any{"row": rown},
-		})
-	}
+				line := fmt.Sprintf("%s: %s", header[i], value)
+				content = append(content, line)
+			}
 
-	return docs, nil
+			rown++
+			if !yield(schema.Document{
+				PageContent: strings.Join(content, "\n"),
+				Metadata:    map[string]any{"row": rown},
+			}) {
+				return nil
+			}
+		}
+	})
 }
 
 // LoadAndSplit reads text data from the io.Reader and splits it into multiple
@@ -0,0 +1,396 @@
+package documentloaders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// _defaultDirectoryConcurrency is the default number of files loaded at the
+// same time by the directory loader.
+const _defaultDirectoryConcurrency = 4
+
+// _sniffLen is the number of bytes used to detect the content type of a file.
+const _sniffLen = 512
+
+// FileLoaderFunc creates the loader for the contents of a file of the given size.
+type FileLoaderFunc func(r io.Reader, size int64) (Loader, error)
+
+// FileError is the error returned by the directory loader for a file that could
+// not be loaded.
+type FileError struct {
+	Path string
+	Err  error
+}
+
+func (e *FileError) Error() string {
+	return fmt.Sprintf("load %s: %s", e.Path, e.Err)
+}
+
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// Directory loads the files of a directory tree, using the loader registered
+// for the extension or, failing that, the detected MIME type of each file.
+// Files with no registered loader are skipped.
+type Directory struct {
+	root        string
+	fsys        fs.FS
+	include     []string
+	exclude     []string
+	extLoaders  map[string]FileLoaderFunc
+	mimeLoaders map[string]FileLoaderFunc
+	concurrency int
+}
+
+var _ Loader = &Directory{}
+
+// DirectoryOption is a function for configuring the directory loader.
+type DirectoryOption func(d *Directory)
+
+// WithIncludeGlobs sets the glob patterns of the files to load. Patterns
+// without a '/' are matched against the file name, others against the path
+// relative to the root, where "**" matches any number of directories. By
+// default, all files are loaded.
+func WithIncludeGlobs(patterns ...string) DirectoryOption {
+	return func(d *Directory) {
+		d.include = patterns
+	}
+}
+
+// WithExcludeGlobs sets the glob patterns of the files and directories to
+// skip. Patterns are matched as for WithIncludeGlobs.
+func WithExcludeGlobs(patterns ...string) DirectoryOption {
+	return func(d *Directory) {
+		d.exclude = patterns
+	}
+}
+
+// WithFileLoader registers the loader for the files with the given extension,
+// such as ".txt", replacing the default loader for that extension if any.
+func WithFileLoader(ext string, loader FileLoaderFunc) DirectoryOption {
+	return func(d *Directory) {
+		d.extLoaders[strings.ToLower(ext)] = loader
+	}
+}
+
+// WithMIMETypeLoader registers the loader for the files with the given MIME
+// type, such as "text/plain", used for files with no loader for their
+// extension.
+func WithMIMETypeLoader(mimeType string, loader FileLoaderFunc) DirectoryOption {
+	return func(d *Directory) {
+		d.mimeLoaders[mimeType] = loader
+	}
+}
+
+// WithConcurrency sets the number of files loaded at the same time.
+func WithConcurrency(n int) DirectoryOption {
+	return func(d *Directory) {
+		d.concurrency = n
+	}
+}
+
+// WithFS sets the file system the directory is read from. The root passed to
+// NewDirectory is then only used for the source metadata of the documents.
+func WithFS(fsys fs.FS) DirectoryOption {
+	return func(d *Directory) {
+		d.fsys = fsys
+	}
+}
+
+// NewDirectory creates a new directory loader for the tree at root. Text,
+// markdown, CSV, HTML, JSON, JSON Lines, email, PDF, DOCX, XLSX, PPTX and EPUB
+// files are loaded by default.
+func NewDirectory(root string, opts ...DirectoryOption) *Directory {
+	d := &Directory{
+		root:        root,
+		extLoaders:  defaultExtensionLoaders(),
+		mimeLoaders: defaultMIMETypeLoaders(),
+		concurrency: _defaultDirectoryConcurrency,
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	if d.fsys == nil {
+		d.fsys = os.DirFS(root)
+	}
+	if d.concurrency <= 0 {
+		d.concurrency = 1
+	}
+	return d
+}
+
+// Load loads the files of the directory tree concurrently and returns their
+// documents, in the order of the file paths. Besides the metadata set by the
+// loader of the file, every document has the path of the file as "source", its
+// size in bytes as "size" and its modification time in RFC 3339 format as
+// "modified". A file that fails to load does not stop the others from being
+// loaded: the documents of the other files are returned, together with an
+// error joining a *FileError for every file that failed.
+func (d *Directory) Load(ctx context.Context) ([]schema.Document, error) {
+	paths, err := d.files(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([][]schema.Document, len(paths))
+	errs := make([]error, len(paths))
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < d.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				results[i], errs[i] = d.loadFile(ctx, paths[i])
+			}
+		}()
+	}
+	for i := range paths {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	docs := make([]schema.Document, 0, len(paths))
+	for _, result := range results {
+		docs = append(docs, result...)
+	}
+	return docs, errors.Join(errs...)
+}
+
+// LoadAndSplit loads the files of the directory tree and splits the documents
+// using a text splitter. As with Load, the documents of the files that were
+// loaded are returned even if some files failed.
+func (d *Directory) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, loadErr := d.Load(ctx)
+	if docs == nil && loadErr != nil {
+		return nil, loadErr
+	}
+
+	split, err := textsplitter.SplitDocuments(splitter, docs)
+	if err != nil {
+		return nil, err
+	}
+	return split, loadErr
+}
+
+// files returns the paths of the files to load, relative to the root.
+func (d *Directory) files(ctx context.Context) ([]string, error) {
+	var paths []string
+	err := fs.WalkDir(d.fsys, ".", func(p string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if p == "." {
+			return nil
+		}
+		if matchAnyGlob(d.exclude, p) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() && (len(d.include) == 0 || matchAnyGlob(d.include, p)) {
+			paths = append(paths, p)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+func (d *Directory) loadFile(ctx context.Context, p string) ([]schema.Document, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	source := filepath.Join(d.root, filepath.FromSlash(p))
+	docs, err := d.loadSource(ctx, p, source)
+	if err != nil {
+		return nil, &FileError{Path: source, Err: err}
+	}
+	return docs, nil
+}
+
+func (d *Directory) loadSource(ctx context.Context, p, source string) ([]schema.Document, error) {
+	f, err := d.fsys.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	var r io.Reader = f
+	loaderFunc, ok := d.extLoaders[strings.ToLower(path.Ext(p))]
+	if !ok {
+		// Detect the MIME type from the extension, then from the content.
+		head := make([]byte, _sniffLen)
+		n, err := io.ReadFull(f, head)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return nil, err
+		}
+		head = head[:n]
+		if loaderFunc, ok = d.mimeLoaders[mediaType(mime.TypeByExtension(path.Ext(p)))]; !ok {
+			loaderFunc, ok = d.mimeLoaders[mediaType(http.DetectContentType(head))]
+		}
+		if !ok {
+			return nil, nil
+		}
+		r = io.MultiReader(bytes.NewReader(head), f)
+	}
+
+	loader, err := loaderFunc(r, info.Size())
+	if err != nil {
+		return nil, err
+	}
+	docs, err := loader.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range docs {
+		if docs[i].Metadata == nil {
+			docs[i].Metadata = map[string]any{}
+		}
+		docs[i].Metadata["source"] = source
+		docs[i].Metadata["size"] = info.Size()
+		docs[i].Metadata["modified"] = info.ModTime().UTC().Format(time.RFC3339)
+	}
+	return docs, nil
+}
+
+// defaultExtensionLoaders returns the default loaders by file extension.
+func defaultExtensionLoaders() map[string]FileLoaderFunc {
+	return map[string]FileLoaderFunc{
+		".txt":    textLoader,
+		".md":     textLoader,
+		".csv":    csvLoader,
+		".html":   htmlLoader,
+		".htm":    htmlLoader,
+		".json":   jsonLoader,
+		".jsonl":  jsonLoader,
+		".ndjson": jsonLoader,
+		".eml":    emlLoader,
+		".mbox":   mboxLoader,
+		".pdf":    readerAtLoader(pdfLoader),
+		".docx":   readerAtLoader(docxLoader),
+		".xlsx":   readerAtLoader(xlsxLoader),
+		".pptx":   readerAtLoader(pptxLoader),
+		".epub":   readerAtLoader(epubLoader),
+	}
+}
+
+// defaultMIMETypeLoaders returns the default loaders by MIME type.
+func defaultMIMETypeLoaders() map[string]FileLoaderFunc {
+	return map[string]FileLoaderFunc{
+		"text/plain":       textLoader,
+		"text/markdown":    textLoader,
+		"text/csv":         csvLoader,
+		"text/html":        htmlLoader,
+		"application/json": jsonLoader,
+		"message/rfc822":   emlLoader,
+		"application/mbox": mboxLoader,
+		"application/pdf":  readerAtLoader(pdfLoader),
+	}
+}
+
+func textLoader(r io.Reader, _ int64) (Loader, error) { return NewText(r), nil }
+func csvLoader(r io.Reader, _ int64) (Loader, error)  { return NewCSV(r), nil }
+func htmlLoader(r io.Reader, _ int64) (Loader, error) { return NewHTML(r), nil }
+func jsonLoader(r io.Reader, _ int64) (Loader, error) { return NewJSON(r), nil }
+func emlLoader(r io.Reader, _ int64) (Loader, error)  { return NewEML(r), nil }
+func mboxLoader(r io.Reader, _ int64) (Loader, error) { return NewMbox(r), nil }
+
+// readerAtLoader returns a FileLoaderFunc for a loader reading from an
+// io.ReaderAt, reading the file in memory if it can not be read at random
+// offsets.
+func readerAtLoader(newLoader func(r io.ReaderAt, size int64) Loader) FileLoaderFunc {
+	return func(r io.Reader, size int64) (Loader, error) {
+		if ra, ok := r.(io.ReaderAt); ok {
+			return newLoader(ra, size), nil
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+		return newLoader(bytes.NewReader(data), int64(len(data))), nil
+	}
+}
+
+func pdfLoader(r io.ReaderAt, size int64) Loader  { return NewPDF(r, size) }
+func docxLoader(r io.ReaderAt, size int64) Loader { return NewDOCX(r, size) }
+func xlsxLoader(r io.ReaderAt, size int64) Loader { return NewXLSX(r, size) }
+func pptxLoader(r io.ReaderAt, size int64) Loader { return NewPPTX(r, size) }
+func epubLoader(r io.ReaderAt, size int64) Loader { return NewEPUB(r, size) }
+
+// mediaType returns the media type of a MIME type, without its parameters.
+func mediaType(mimeType string) string {
+	mediaType, _, _ := strings.Cut(mimeType, ";")
+	return strings.TrimSpace(mediaType)
+}
+
+// matchAnyGlob reports whether the slash separated path p matches any of the
+// glob patterns.
+func matchAnyGlob(patterns []string, p string) bool {
+	for _, pattern := range patterns {
+		if !strings.Contains(pattern, "/") {
+			if ok, _ := path.Match(pattern, path.Base(p)); ok {
+				return true
+			}
+			continue
+		}
+		if matchGlobSegments(strings.Split(pattern, "/"), strings.Split(p, "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchGlobSegments matches path segments against pattern segments, where a
+// "**" segment matches any number of path segments.
+func matchGlobSegments(pattern, segments []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(segments); i++ {
+				if matchGlobSegments(pattern[1:], segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], segments[0]); !ok {
+			return false
+		}
+		pattern, segments = pattern[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
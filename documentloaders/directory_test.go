@@ -0,0 +1,127 @@
+package documentloaders
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirectoryLoader(t *testing.T) {
+	t.Parallel()
+
+	loader := NewDirectory("./testdata", WithConcurrency(2), WithIncludeGlobs("test.*", "sample*.pdf"))
+	docs, err := loader.Load(context.Background())
+
+	// The password protected PDF fails to load without stopping the others.
+	var fileErr *FileError
+	require.ErrorAs(t, err, &fileErr)
+	assert.Equal(t, filepath.Join("testdata", "sample_password.pdf"), fileErr.Path)
+
+	sources := map[string]int{}
+	for _, doc := range docs {
+		sources[filepath.Base(doc.Metadata["source"].(string))]++
+		assert.Contains(t, doc.Metadata, "size")
+		assert.Contains(t, doc.Metadata, "modified")
+	}
+	assert.Equal(t, map[string]int{
+		"sample.pdf": 2,
+		"test.csv":   20,
+		"test.html":  1,
+		"test.txt":   1,
+	}, sources)
+}
+
+func TestDirectoryLoaderGlobs(t *testing.T) {
+	t.Parallel()
+
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	fsys := fstest.MapFS{
+		"README.md":          {Data: []byte("# Readme"), ModTime: modified},
+		"docs/guide.md":      {Data: []byte("# Guide")},
+		"docs/api/ref.md":    {Data: []byte("# Reference")},
+		"docs/notes":         {Data: []byte("plain notes without extension")},
+		"docs/image.png":     {Data: []byte("\x89PNG\r\n\x1a\n")},
+		"vendor/lib/lib.md":  {Data: []byte("# Vendored")},
+		"docs/data.custom":   {Data: []byte("custom")},
+		"docs/api/skip.html": {Data: []byte("<p>skipped</p>")},
+	}
+
+	tests := map[string]struct {
+		opts     []DirectoryOption
+		expected []string
+	}{
+		"all": {
+			expected: []string{
+				"README.md", "docs/api/ref.md", "docs/api/skip.html", "docs/data.custom", "docs/guide.md", "docs/notes",
+				"vendor/lib/lib.md",
+			},
+		},
+		"include and exclude": {
+			opts:     []DirectoryOption{WithIncludeGlobs("*.md"), WithExcludeGlobs("vendor", "docs/api/**")},
+			expected: []string{"README.md", "docs/guide.md"},
+		},
+		"double star": {
+			opts:     []DirectoryOption{WithIncludeGlobs("docs/**/*.md")},
+			expected: []string{"docs/api/ref.md", "docs/guide.md"},
+		},
+		"custom loader": {
+			opts: []DirectoryOption{
+				WithIncludeGlobs("*.custom"),
+				WithFileLoader(".custom", func(r io.Reader, _ int64) (Loader, error) { return NewText(r), nil }),
+			},
+			expected: []string{"docs/data.custom"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			loader := NewDirectory("root", append(tc.opts, WithFS(fsys))...)
+			docs, err := loader.Load(context.Background())
+			require.NoError(t, err)
+
+			sources := make([]string, 0, len(docs))
+			for _, doc := range docs {
+				rel, err := filepath.Rel("root", doc.Metadata["source"].(string))
+				require.NoError(t, err)
+				sources = append(sources, filepath.ToSlash(rel))
+			}
+			assert.Equal(t, tc.expected, sources)
+		})
+	}
+
+	docs, err := NewDirectory("root", WithFS(fsys), WithIncludeGlobs("README.md")).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, map[string]any{
+		"source":   filepath.Join("root", "README.md"),
+		"size":     int64(8),
+		"modified": "2024-01-02T03:04:05Z",
+	}, docs[0].Metadata)
+}
+
+func TestDirectoryLoaderFileError(t *testing.T) {
+	t.Parallel()
+
+	errBroken := errors.New("broken")
+	fsys := fstest.MapFS{
+		"a.txt": {Data: []byte("a")},
+		"b.bad": {Data: []byte("b")},
+	}
+	loader := NewDirectory(".", WithFS(fsys), WithFileLoader(".bad", func(io.Reader, int64) (Loader, error) {
+		return nil, errBroken
+	}))
+
+	docs, err := loader.Load(context.Background())
+	require.ErrorIs(t, err, errBroken)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "a", docs[0].PageContent)
+}
@@ -14,3 +14,58 @@ type Loader interface {
 	// LoadAndSplit loads from a source and splits the documents using a text splitter.
 	LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error)
 }
+
+// LazyLoader is the interface for loaders that can load documents one at a
+// time, without holding all the documents of the source in memory.
+type LazyLoader interface {
+	Loader
+	// LazyLoad loads from a source and sends the documents on the returned
+	// channel as they are read. If loading fails, the error is sent as the last
+	// result. The channel is closed when loading is done or ctx is canceled; the
+	// caller must either read the channel until it is closed or cancel ctx.
+	LazyLoad(ctx context.Context) <-chan DocumentResult
+}
+
+// DocumentResult is a document loaded by a LazyLoader, or the error that
+// stopped the loading.
+type DocumentResult struct {
+	Document schema.Document
+	Err      error
+}
+
+// lazyLoad runs load in a goroutine, sending the documents it yields on the
+// returned channel. yield returns false when ctx is canceled, in which case
+// load should return.
+func lazyLoad(ctx context.Context, load func(yield func(schema.Document) bool) error) <-chan DocumentResult {
+	results := make(chan DocumentResult)
+	go func() {
+		defer close(results)
+		send := func(result DocumentResult) bool {
+			select {
+			case results <- result:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+		if err := load(func(doc schema.Document) bool { return send(DocumentResult{Document: doc}) }); err != nil {
+			send(DocumentResult{Err: err})
+		}
+	}()
+	return results
+}
+
+// collectDocuments reads all the results of a lazy load.
+func collectDocuments(ctx context.Context, results <-chan DocumentResult) ([]schema.Document, error) {
+	var docs []schema.Document
+	for result := range results {
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		docs = append(docs, result.Document)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
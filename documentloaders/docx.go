@@ -0,0 +1,224 @@
+package documentloaders
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// DOCX loads the text of a Word document from an io.ReaderAt. Headings, lists
+// and tables are formatted as markdown.
+type DOCX struct {
+	r io.ReaderAt
+	s int64
+}
+
+var _ LazyLoader = DOCX{}
+
+// NewDOCX creates a new Word document loader with an io.ReaderAt and the size
+// of the document.
+func NewDOCX(r io.ReaderAt, size int64) DOCX {
+	return DOCX{r: r, s: size}
+}
+
+// Load reads the document and returns a single document with its text, and
+// its title in the metadata if it is set.
+func (d DOCX) Load(ctx context.Context) ([]schema.Document, error) {
+	return collectDocuments(ctx, d.LazyLoad(ctx))
+}
+
+// LazyLoad reads the document and sends the document returned by Load.
+func (d DOCX) LazyLoad(ctx context.Context) <-chan DocumentResult {
+	return lazyLoad(ctx, func(yield func(schema.Document) bool) error {
+		archive, err := openZipArchive(d.r, d.s)
+		if err != nil {
+			return err
+		}
+		text, err := docxText(archive)
+		if err != nil {
+			return err
+		}
+
+		metadata := map[string]any{}
+		if title := archive.coreTitle(); title != "" {
+			metadata["title"] = title
+		}
+		yield(schema.Document{PageContent: text, Metadata: metadata})
+		return nil
+	})
+}
+
+// LoadAndSplit reads the document and splits it into multiple documents using
+// a text splitter.
+func (d DOCX) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := d.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+// docxTable is a table being read from a Word document.
+type docxTable struct {
+	rows [][]string
+	row  []string
+	cell []string
+}
+
+// docxText returns the text of the main part of a Word document.
+func docxText(archive *zipArchive) (string, error) { //nolint:cyclop,funlen
+	decoder, closeFn, err := archive.decoder("word/document.xml")
+	if err != nil {
+		return "", err
+	}
+	defer closeFn()
+
+	var (
+		blocks    []string
+		tables    []*docxTable
+		paragraph strings.Builder
+		style     string
+		listItem  bool
+		inRun     bool
+		inText    bool
+	)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "p":
+				paragraph.Reset()
+				style, listItem = "", false
+			case "pStyle":
+				style = attr(t, "val")
+			case "numPr":
+				listItem = true
+			case "r":
+				inRun = true
+			case "t":
+				inText = true
+			case "tab":
+				if inRun {
+					paragraph.WriteString("\t")
+				}
+			case "br", "cr":
+				if inRun {
+					paragraph.WriteString("\n")
+				}
+			case "tbl":
+				tables = append(tables, &docxTable{})
+			case "tr":
+				if n := len(tables); n > 0 {
+					tables[n-1].row = nil
+				}
+			case "tc":
+				if n := len(tables); n > 0 {
+					tables[n-1].cell = nil
+				}
+			}
+		case xml.CharData:
+			if inText {
+				paragraph.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "r":
+				inRun = false
+			case "t":
+				inText = false
+			case "p":
+				text := strings.TrimSpace(paragraph.String())
+				if text == "" {
+					continue
+				}
+				if n := len(tables); n > 0 {
+					tables[n-1].cell = append(tables[n-1].cell, text)
+					continue
+				}
+				blocks = append(blocks, docxParagraph(text, style, listItem))
+			case "tc":
+				if n := len(tables); n > 0 {
+					table := tables[n-1]
+					table.row = append(table.row, strings.Join(table.cell, " "))
+				}
+			case "tr":
+				if n := len(tables); n > 0 {
+					table := tables[n-1]
+					table.rows = append(table.rows, table.row)
+				}
+			case "tbl":
+				n := len(tables)
+				if n == 0 {
+					continue
+				}
+				table := tables[n-1]
+				tables = tables[:n-1]
+				if len(tables) > 0 {
+					// Nested tables are flattened into the cell of the outer table.
+					parent := tables[len(tables)-1]
+					for _, row := range table.rows {
+						parent.cell = append(parent.cell, strings.Join(row, " "))
+					}
+					continue
+				}
+				if md := markdownTable(table.rows); md != "" {
+					blocks = append(blocks, md)
+				}
+			}
+		}
+	}
+
+	return mergeListBlocks(blocks), nil
+}
+
+// docxParagraph formats a paragraph as markdown according to its style.
+func docxParagraph(text, style string, listItem bool) string {
+	lower := strings.ToLower(strings.ReplaceAll(style, " ", ""))
+	switch {
+	case lower == "title":
+		return "# " + text
+	case strings.HasPrefix(lower, "heading"):
+		level, err := strconv.Atoi(strings.TrimFunc(lower[len("heading"):], func(r rune) bool {
+			return !unicode.IsDigit(r)
+		}))
+		if err != nil || level < 1 {
+			level = 1
+		}
+		return strings.Repeat("#", min(level, 6)) + " " + text
+	case listItem || strings.HasPrefix(lower, "listparagraph") || strings.HasPrefix(lower, "listbullet"):
+		return "- " + text
+	default:
+		return text
+	}
+}
+
+// mergeListBlocks joins blocks with blank lines, except between list items.
+func mergeListBlocks(blocks []string) string {
+	var b strings.Builder
+	for i, block := range blocks {
+		if i > 0 {
+			if strings.HasPrefix(block, "- ") && strings.HasPrefix(blocks[i-1], "- ") {
+				b.WriteString("\n")
+			} else {
+				b.WriteString("\n\n")
+			}
+		}
+		b.WriteString(block)
+	}
+	return b.String()
+}
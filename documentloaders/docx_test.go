@@ -0,0 +1,32 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDOCXLoader(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/sample.docx")
+	require.NoError(t, err)
+	info, err := file.Stat()
+	require.NoError(t, err)
+
+	docs, err := NewDOCX(file, info.Size()).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	expected := "# Quarterly report\n\n" +
+		"# Summary\n\n" +
+		"Revenue grew\t by 12%.\n\n" +
+		"## Highlights\n\n" +
+		"- New customers\n- Lower churn\n\n" +
+		"| Region | Sales |\n| --- | --- |\n| EMEA | 1,200 |\n\n" +
+		"Thanks for reading."
+	assert.Equal(t, expected, docs[0].PageContent)
+	assert.Equal(t, map[string]any{"title": "Q3 report"}, docs[0].Metadata)
+}
@@ -0,0 +1,409 @@
+package documentloaders
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// Email loads email messages from an io.Reader, either a single message in
+// EML format or a mailbox in mbox format.
+type Email struct {
+	r           io.Reader
+	mbox        bool
+	attachments bool
+}
+
+var _ LazyLoader = Email{}
+
+// EmailOptions are options for the email loaders.
+type EmailOptions func(e *Email)
+
+// WithAttachments makes the loader also return the documents of the
+// attachments of the messages, loaded with the loader registered by default in
+// a Directory for their file extension or MIME type. Attachments that no
+// loader can read are skipped.
+func WithAttachments() EmailOptions {
+	return func(e *Email) {
+		e.attachments = true
+	}
+}
+
+// NewEML creates a new loader for a single email message in EML (RFC 5322)
+// format.
+func NewEML(r io.Reader, opts ...EmailOptions) Email {
+	return newEmail(r, false, opts)
+}
+
+// NewMbox creates a new loader for the messages of a mailbox in mbox format.
+func NewMbox(r io.Reader, opts ...EmailOptions) Email {
+	return newEmail(r, true, opts)
+}
+
+func newEmail(r io.Reader, mbox bool, opts []EmailOptions) Email {
+	e := Email{r: r, mbox: mbox}
+	for _, opt := range opts {
+		opt(&e)
+	}
+	return e
+}
+
+// Load reads the messages and returns a document per message, with its plain
+// text body, or its HTML body converted to markdown if it has no plain text
+// body. The sender, recipients, subject, date, message ID and thread
+// references are set as "from", "to", "cc", "subject", "date", "message_id",
+// "in_reply_to" and "references" in the metadata. The documents of the
+// attachments, if enabled, follow the document of their message, with the
+// file name of the attachment set as "attachment" and the subject and
+// message ID of the message in the metadata. Attachments that fail to load
+// are skipped, with their errors set as "attachment_errors" in the metadata of
+// their message.
+func (e Email) Load(ctx context.Context) ([]schema.Document, error) {
+	return collectDocuments(ctx, e.LazyLoad(ctx))
+}
+
+// LazyLoad reads the messages and sends the documents returned by Load, one
+// message at a time.
+func (e Email) LazyLoad(ctx context.Context) <-chan DocumentResult {
+	return lazyLoad(ctx, func(yield func(schema.Document) bool) error {
+		if !e.mbox {
+			_, err := e.yieldMessage(ctx, e.r, yield)
+			return err
+		}
+		return readMbox(e.r, func(message []byte) (bool, error) {
+			return e.yieldMessage(ctx, bytes.NewReader(message), yield)
+		})
+	})
+}
+
+// LoadAndSplit reads the messages and splits the documents using a text
+// splitter.
+func (e Email) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := e.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+// yieldMessage parses a message and yields its documents. It returns false if
+// yield did.
+func (e Email) yieldMessage(ctx context.Context, r io.Reader, yield func(schema.Document) bool) (bool, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return false, err
+	}
+	parts := &emailParts{}
+	if err := parts.walk(textproto.MIMEHeader(msg.Header), msg.Body); err != nil {
+		return false, err
+	}
+
+	metadata := emailMetadata(msg.Header)
+	var attachmentDocs []schema.Document
+	if e.attachments {
+		attachmentDocs, err = loadAttachments(ctx, parts.attachments, metadata)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if !yield(schema.Document{PageContent: parts.body(), Metadata: metadata}) {
+		return false, nil
+	}
+	for _, doc := range attachmentDocs {
+		if !yield(doc) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// loadAttachments returns the documents of the attachments of a message with
+// the given metadata. The attachments that fail to load are skipped and their
+// errors are set as "attachment_errors" in the metadata of the message. Only
+// the cancellation of ctx is returned as an error.
+func loadAttachments(
+	ctx context.Context,
+	attachments []emailAttachment,
+	metadata map[string]any,
+) ([]schema.Document, error) {
+	var (
+		docs     []schema.Document
+		failures []string
+	)
+	for _, attachment := range attachments {
+		attachmentDocs, err := attachment.load(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			failures = append(failures, fmt.Sprintf("%s: %v", attachment.filename, err))
+			continue
+		}
+		for _, doc := range attachmentDocs {
+			if doc.Metadata == nil {
+				doc.Metadata = map[string]any{}
+			}
+			doc.Metadata["attachment"] = attachment.filename
+			for _, key := range []string{"subject", "message_id"} {
+				if value, ok := metadata[key]; ok {
+					doc.Metadata[key] = value
+				}
+			}
+			docs = append(docs, doc)
+		}
+	}
+	if len(failures) > 0 {
+		metadata["attachment_errors"] = failures
+	}
+	return docs, nil
+}
+
+// readMbox splits a mailbox into messages, calling fn for each of them until
+// it returns false or an error. Messages start with a "From " line and the
+// lines of their body escaped as ">From " are unescaped.
+func readMbox(r io.Reader, fn func(message []byte) (bool, error)) error {
+	var message bytes.Buffer
+	started := false
+	previousBlank := true
+	flush := func() (bool, error) {
+		if !started {
+			return true, nil
+		}
+		return fn(message.Bytes())
+	}
+
+	reader := bufio.NewReader(r)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			switch {
+			case previousBlank && bytes.HasPrefix(line, []byte("From ")):
+				if ok, err := flush(); !ok || err != nil {
+					return err
+				}
+				message.Reset()
+				started = true
+			case started:
+				if unescaped := bytes.TrimLeft(line, ">"); len(unescaped) < len(line) &&
+					bytes.HasPrefix(unescaped, []byte("From ")) {
+					line = line[1:]
+				}
+				message.Write(line)
+			}
+			previousBlank = len(bytes.TrimRight(line, "\r\n")) == 0
+		}
+		if errors.Is(err, io.EOF) {
+			_, err := flush()
+			return err
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// emailMetadata returns the metadata of a message from its header.
+func emailMetadata(header mail.Header) map[string]any {
+	metadata := map[string]any{}
+	for key, field := range map[string]string{"from": "From", "to": "To", "cc": "Cc"} {
+		setIfNotEmpty(metadata, key, emailAddresses(header, field))
+	}
+	setIfNotEmpty(metadata, "subject", decodeHeader(header.Get("Subject")))
+	if date, err := header.Date(); err == nil {
+		metadata["date"] = date.UTC().Format(time.RFC3339)
+	}
+	setIfNotEmpty(metadata, "message_id", strings.Trim(header.Get("Message-Id"), " <>"))
+	setIfNotEmpty(metadata, "in_reply_to", strings.Trim(header.Get("In-Reply-To"), " <>"))
+	if references := strings.Fields(header.Get("References")); len(references) > 0 {
+		for i, reference := range references {
+			references[i] = strings.Trim(reference, "<>")
+		}
+		metadata["references"] = references
+	}
+	return metadata
+}
+
+// emailAddresses returns the addresses of an address field, separated by
+// commas, or the decoded field if its addresses cannot be parsed.
+func emailAddresses(header mail.Header, field string) string {
+	addresses, err := header.AddressList(field)
+	if err != nil {
+		return decodeHeader(header.Get(field))
+	}
+	formatted := make([]string, len(addresses))
+	for i, address := range addresses {
+		formatted[i] = address.Address
+		if address.Name != "" {
+			formatted[i] = fmt.Sprintf("%s <%s>", address.Name, address.Address)
+		}
+	}
+	return strings.Join(formatted, ", ")
+}
+
+// decodeHeader decodes the RFC 2047 encoded words of a header value.
+func decodeHeader(value string) string {
+	decoder := &mime.WordDecoder{CharsetReader: charsetReader}
+	decoded, err := decoder.DecodeHeader(value)
+	if err != nil {
+		return value
+	}
+	return decoded
+}
+
+// charsetReader returns a reader converting the text of r from charset to
+// UTF-8.
+func charsetReader(charset string, r io.Reader) (io.Reader, error) {
+	switch strings.ToLower(charset) {
+	case "", "utf-8", "us-ascii":
+		return r, nil
+	}
+	encoding, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, err
+	}
+	return encoding.NewDecoder().Reader(r), nil
+}
+
+// emailParts are the bodies and attachments of a message.
+type emailParts struct {
+	plain       []string
+	html        []string
+	attachments []emailAttachment
+}
+
+// emailAttachment is an attachment of a message.
+type emailAttachment struct {
+	filename string
+	mimeType string
+	data     []byte
+}
+
+// walk adds the parts of a message, or of a part of a multipart message.
+func (p *emailParts) walk(header textproto.MIMEHeader, body io.Reader) error {
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", map[string]string{}
+	}
+
+	if strings.HasPrefix(mediaType, "multipart/") {
+		reader := multipart.NewReader(body, params["boundary"])
+		for {
+			part, err := reader.NextRawPart()
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := p.walk(part.Header, part); err != nil {
+				return err
+			}
+		}
+	}
+
+	data, err := io.ReadAll(transferDecoder(header.Get("Content-Transfer-Encoding"), body))
+	if err != nil {
+		return err
+	}
+
+	disposition, dispositionParams, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+	filename := decodeHeader(dispositionParams["filename"])
+	if filename == "" {
+		filename = decodeHeader(params["name"])
+	}
+	if disposition == "attachment" || filename != "" || (mediaType != "text/plain" && mediaType != "text/html") {
+		p.attachments = append(p.attachments, emailAttachment{filename: filename, mimeType: mediaType, data: data})
+		return nil
+	}
+
+	text, err := decodeCharset(params["charset"], data)
+	if err != nil {
+		return err
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	if mediaType == "text/html" {
+		p.html = append(p.html, text)
+	} else {
+		p.plain = append(p.plain, text)
+	}
+	return nil
+}
+
+// body returns the plain text bodies of the message or, if it has none, its
+// HTML bodies converted to markdown.
+func (p *emailParts) body() string {
+	if len(p.plain) > 0 {
+		return strings.TrimSpace(strings.Join(p.plain, "\n\n"))
+	}
+	texts := make([]string, 0, len(p.html))
+	for _, html := range p.html {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(html))
+		if err != nil {
+			continue
+		}
+		texts = append(texts, htmlToMarkdown(doc, nil))
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// load loads the attachment with the default loader for its file extension or
+// MIME type, and returns no documents if there is none.
+func (a emailAttachment) load(ctx context.Context) ([]schema.Document, error) {
+	newLoader, ok := defaultExtensionLoaders()[strings.ToLower(path.Ext(a.filename))]
+	if !ok {
+		newLoader, ok = defaultMIMETypeLoaders()[a.mimeType]
+	}
+	if !ok {
+		return nil, nil
+	}
+	loader, err := newLoader(bytes.NewReader(a.data), int64(len(a.data)))
+	if err != nil {
+		return nil, err
+	}
+	return loader.Load(ctx)
+}
+
+// transferDecoder returns a reader decoding body from its content transfer
+// encoding.
+func transferDecoder(encoding string, body io.Reader) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(encoding)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, body)
+	case "quoted-printable":
+		return quotedprintable.NewReader(body)
+	default:
+		return body
+	}
+}
+
+// decodeCharset converts text from charset to UTF-8.
+func decodeCharset(charset string, data []byte) (string, error) {
+	r, err := charsetReader(charset, bytes.NewReader(data))
+	if err != nil {
+		// Keep the text as is if its charset is unknown.
+		return string(data), nil //nolint:nilerr
+	}
+	decoded, err := io.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
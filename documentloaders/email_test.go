@@ -0,0 +1,119 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEMLLoader(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/sample.eml")
+	require.NoError(t, err)
+	defer file.Close()
+
+	docs, err := NewEML(file).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	assert.Equal(t, "Hi Alice,\n\nHere is the quarterly report. The café budget is unchanged.\n\nJosé", docs[0].PageContent)
+	assert.Equal(t, map[string]any{
+		"from":        "José García <jose@example.com>",
+		"to":          "Alice <alice@example.com>, bob@example.com",
+		"cc":          "Carol <carol@example.com>",
+		"subject":     "Quarterly report ✓",
+		"date":        "2024-09-02T08:15:00Z",
+		"message_id":  "report-1@example.com",
+		"in_reply_to": "request-1@example.com",
+		"references":  []string{"thread-0@example.com", "request-1@example.com"},
+	}, docs[0].Metadata)
+}
+
+func TestEMLLoaderWithAttachments(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/sample.eml")
+	require.NoError(t, err)
+	defer file.Close()
+
+	docs, err := NewEML(file, WithAttachments()).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "Quarterly numbers are attached.\nRevenue grew by 12%.\n", docs[1].PageContent)
+	assert.Equal(t, map[string]any{
+		"attachment": "report.txt",
+		"subject":    "Quarterly report ✓",
+		"message_id": "report-1@example.com",
+	}, docs[1].Metadata)
+}
+
+func TestMboxLoader(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/sample.mbox")
+	require.NoError(t, err)
+	defer file.Close()
+
+	docs, err := NewMbox(file).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "Thanks, looks good.\nFrom the numbers, we are on track.", docs[0].PageContent)
+	assert.Equal(t, "Re: Quarterly report", docs[0].Metadata["subject"])
+	assert.Equal(t, "report-1@example.com", docs[0].Metadata["in_reply_to"])
+	assert.Equal(t, []string{"report-1@example.com"}, docs[0].Metadata["references"])
+
+	assert.Equal(t, "# Team lunch\n\nSee you at the café at *noon*.", docs[1].PageContent)
+	assert.Equal(t, "bob@example.com", docs[1].Metadata["from"])
+	assert.Equal(t, "2024-09-03T09:30:00Z", docs[1].Metadata["date"])
+	assert.NotContains(t, docs[1].Metadata, "in_reply_to")
+}
+
+func TestMboxLoaderWithCorruptAttachment(t *testing.T) {
+	t.Parallel()
+	mbox := strings.Join([]string{
+		"From alice@example.com Tue Sep  3 08:00:00 2024",
+		"From: Alice <alice@example.com>",
+		"Subject: Broken report",
+		"Message-ID: <broken-1@example.com>",
+		"MIME-Version: 1.0",
+		`Content-Type: multipart/mixed; boundary="b"`,
+		"",
+		"--b",
+		"Content-Type: text/plain",
+		"",
+		"The report is attached.",
+		"--b",
+		"Content-Type: application/pdf",
+		`Content-Disposition: attachment; filename="report.pdf"`,
+		"",
+		"not a pdf",
+		"--b",
+		"Content-Type: text/plain",
+		`Content-Disposition: attachment; filename="notes.txt"`,
+		"",
+		"Some notes.",
+		"--b--",
+		"",
+		"From bob@example.com Tue Sep  3 09:30:00 2024",
+		"From: bob@example.com",
+		"Subject: Team lunch",
+		"",
+		"See you at noon.",
+		"",
+	}, "\n")
+
+	docs, err := NewMbox(strings.NewReader(mbox), WithAttachments()).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+
+	assert.Equal(t, "The report is attached.", docs[0].PageContent)
+	require.Len(t, docs[0].Metadata["attachment_errors"], 1)
+	assert.Contains(t, docs[0].Metadata["attachment_errors"].([]string)[0], "report.pdf: ")
+	assert.Equal(t, "notes.txt", docs[1].Metadata["attachment"])
+	assert.Equal(t, "See you at noon.", docs[2].PageContent)
+	assert.NotContains(t, docs[2].Metadata, "attachment_errors")
+}
@@ -0,0 +1,163 @@
+package documentloaders
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// EPUB loads the chapters of an EPUB e-book from an io.ReaderAt.
+type EPUB struct {
+	r io.ReaderAt
+	s int64
+}
+
+var _ LazyLoader = EPUB{}
+
+// NewEPUB creates a new EPUB e-book loader with an io.ReaderAt and the size of
+// the e-book.
+func NewEPUB(r io.ReaderAt, size int64) EPUB {
+	return EPUB{r: r, s: size}
+}
+
+// Load reads the e-book and returns a document per chapter, in reading order,
+// with the chapter converted to markdown. The title, author and language of
+// the book are added to the metadata, together with the number of the chapter
+// as "chapter" and its title, if any, as "chapter_title".
+func (e EPUB) Load(ctx context.Context) ([]schema.Document, error) {
+	return collectDocuments(ctx, e.LazyLoad(ctx))
+}
+
+// LazyLoad reads the e-book and sends the documents returned by Load, one
+// chapter at a time.
+func (e EPUB) LazyLoad(ctx context.Context) <-chan DocumentResult {
+	return lazyLoad(ctx, func(yield func(schema.Document) bool) error {
+		archive, err := openZipArchive(e.r, e.s)
+		if err != nil {
+			return err
+		}
+		pkg, packagePart, err := epubPackage(archive)
+		if err != nil {
+			return err
+		}
+
+		items := make(map[string]string, len(pkg.Manifest))
+		for _, item := range pkg.Manifest {
+			items[item.ID] = item.Href
+		}
+
+		chapter := 0
+		for _, itemref := range pkg.Spine {
+			href, ok := items[itemref.IDRef]
+			if !ok {
+				continue
+			}
+			if unescaped, err := url.PathUnescape(href); err == nil {
+				href = unescaped
+			}
+			doc, ok, err := epubChapter(archive, path.Join(path.Dir(packagePart), href))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+
+			chapter++
+			doc.Metadata["chapter"] = chapter
+			setIfNotEmpty(doc.Metadata, "title", pkg.Title)
+			setIfNotEmpty(doc.Metadata, "author", strings.Join(pkg.Creators, ", "))
+			setIfNotEmpty(doc.Metadata, "language", pkg.Language)
+			if !yield(doc) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// LoadAndSplit reads the e-book and splits the documents using a text
+// splitter.
+func (e EPUB) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := e.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+// epubPackageDocument is the package document of an EPUB, listing its
+// metadata, its content documents and their reading order.
+type epubPackageDocument struct {
+	Title    string   `xml:"metadata>title"`
+	Creators []string `xml:"metadata>creator"`
+	Language string   `xml:"metadata>language"`
+	Manifest []struct {
+		ID   string `xml:"id,attr"`
+		Href string `xml:"href,attr"`
+	} `xml:"manifest>item"`
+	Spine []struct {
+		IDRef string `xml:"idref,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// epubPackage returns the package document of an EPUB and its part name.
+func epubPackage(archive *zipArchive) (*epubPackageDocument, string, error) {
+	var container struct {
+		Rootfiles []struct {
+			FullPath string `xml:"full-path,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	if err := archive.unmarshal("META-INF/container.xml", &container); err != nil {
+		return nil, "", err
+	}
+	if len(container.Rootfiles) == 0 {
+		return nil, "", ErrMissingPart
+	}
+
+	part := container.Rootfiles[0].FullPath
+	pkg := &epubPackageDocument{}
+	if err := archive.unmarshal(part, pkg); err != nil {
+		return nil, "", err
+	}
+	return pkg, part, nil
+}
+
+// epubChapter returns the document of a chapter, and false if the chapter has
+// no text.
+func epubChapter(archive *zipArchive, part string) (schema.Document, bool, error) {
+	data, err := archive.read(part)
+	if err != nil {
+		return schema.Document{}, false, err
+	}
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return schema.Document{}, false, err
+	}
+
+	if strings.TrimSpace(doc.Find("body").Text()) == "" {
+		return schema.Document{}, false, nil
+	}
+
+	metadata := map[string]any{}
+	title := strings.TrimSpace(doc.Find("h1, h2, h3").First().Text())
+	if title == "" {
+		title = strings.TrimSpace(doc.Find("head title").First().Text())
+	}
+	setIfNotEmpty(metadata, "chapter_title", title)
+
+	return schema.Document{PageContent: htmlToMarkdown(doc, nil), Metadata: metadata}, true, nil
+}
+
+func setIfNotEmpty(metadata map[string]any, key, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		metadata[key] = value
+	}
+}
@@ -0,0 +1,35 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEPUBLoader(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/sample.epub")
+	require.NoError(t, err)
+	info, err := file.Stat()
+	require.NoError(t, err)
+
+	docs, err := NewEPUB(file, info.Size()).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "# Seeds\n\nPlant them in *spring*.", docs[0].PageContent)
+	assert.Equal(t, map[string]any{
+		"title":         "The Little Garden",
+		"author":        "Ada Green",
+		"language":      "en",
+		"chapter":       1,
+		"chapter_title": "Seeds",
+	}, docs[0].Metadata)
+
+	assert.Equal(t, "Pick the tomatoes when red.", docs[1].PageContent)
+	assert.Equal(t, 2, docs[1].Metadata["chapter"])
+	assert.Equal(t, "Harvest", docs[1].Metadata["chapter_title"])
+}
@@ -0,0 +1,412 @@
+package documentloaders
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// _binarySniffLen is the number of bytes of a file checked for NUL bytes to
+// detect binary files, as git does.
+const _binarySniffLen = 8000
+
+// _defaultGitSkipDirs are the names of the directories skipped by default,
+// holding vendored or generated code.
+var _defaultGitSkipDirs = []string{"vendor", "node_modules", "third_party", "bower_components"} //nolint:gochecknoglobals
+
+// Git loads the files of a local git repository, either from its working tree
+// or from a commit, without running git.
+type Git struct {
+	path     string
+	ref      string
+	include  []string
+	exclude  []string
+	skipDirs []string
+}
+
+var _ LazyLoader = Git{}
+
+// GitOptions are options for the git repository loader.
+type GitOptions func(g *Git)
+
+// WithGitRef makes the loader read the files of a commit instead of the
+// working tree. The ref can be a branch, a tag, a commit hash or any revision
+// understood by git, such as "HEAD~2".
+func WithGitRef(ref string) GitOptions {
+	return func(g *Git) {
+		g.ref = ref
+	}
+}
+
+// WithGitIncludeGlobs sets the glob patterns of the files to load, as for
+// WithIncludeGlobs. By default, all the files are loaded.
+func WithGitIncludeGlobs(patterns ...string) GitOptions {
+	return func(g *Git) {
+		g.include = append(g.include, patterns...)
+	}
+}
+
+// WithGitExcludeGlobs sets the glob patterns of the files and directories to
+// skip, as for WithExcludeGlobs.
+func WithGitExcludeGlobs(patterns ...string) GitOptions {
+	return func(g *Git) {
+		g.exclude = append(g.exclude, patterns...)
+	}
+}
+
+// WithGitSkipDirs sets the names of the directories to skip, wherever they are
+// in the repository. It replaces the default directories holding vendored
+// code: "vendor", "node_modules", "third_party" and "bower_components".
+func WithGitSkipDirs(names ...string) GitOptions {
+	return func(g *Git) {
+		g.skipDirs = names
+	}
+}
+
+// NewGit creates a new loader for the git repository at path.
+func NewGit(path string, opts ...GitOptions) Git {
+	g := Git{path: path, skipDirs: _defaultGitSkipDirs}
+	for _, opt := range opts {
+		opt(&g)
+	}
+	return g
+}
+
+// Load reads the repository and returns a document per text file, skipping
+// binary files. The path of the file, relative to the root of the repository,
+// is set as "path" in the metadata, its programming language, if known, as
+// "language", the hash of the commit read, or of HEAD for the working tree,
+// as "commit" and the author of the last commit changing the file as
+// "last_author". Files of the working tree that are ignored by git are
+// skipped.
+func (g Git) Load(ctx context.Context) ([]schema.Document, error) {
+	return collectDocuments(ctx, g.LazyLoad(ctx))
+}
+
+// LazyLoad reads the repository and sends the documents returned by Load, one
+// file at a time.
+func (g Git) LazyLoad(ctx context.Context) <-chan DocumentResult {
+	return lazyLoad(ctx, func(yield func(schema.Document) bool) error {
+		repo, err := git.PlainOpen(g.path)
+		if err != nil {
+			return err
+		}
+
+		var files []gitFile
+		var commit *object.Commit
+		if g.ref == "" {
+			files, commit, err = g.worktreeFiles(repo)
+		} else {
+			files, commit, err = g.commitFiles(repo)
+		}
+		if err != nil {
+			return err
+		}
+
+		var authors map[string]string
+		if commit != nil {
+			if authors, err = lastAuthors(ctx, repo, commit, files); err != nil {
+				return err
+			}
+		}
+
+		for _, file := range files {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			data, err := file.read()
+			if err != nil {
+				return fmt.Errorf("%s: %w", file.path, err)
+			}
+			if isBinary(data) {
+				continue
+			}
+
+			metadata := map[string]any{"path": file.path}
+			setIfNotEmpty(metadata, "language", gitLanguage(file.path))
+			if commit != nil {
+				metadata["commit"] = commit.Hash.String()
+			}
+			setIfNotEmpty(metadata, "last_author", authors[file.path])
+			if !yield(schema.Document{PageContent: string(data), Metadata: metadata}) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// LoadAndSplit reads the repository and splits the documents using a text
+// splitter.
+func (g Git) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := g.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+// gitFile is a file of a repository, read from the working tree or from a
+// commit.
+type gitFile struct {
+	path string
+	read func() ([]byte, error)
+}
+
+// worktreeFiles returns the files of the working tree that are not ignored,
+// and the HEAD commit, or nil if the repository has no commits.
+func (g Git) worktreeFiles(repo *git.Repository) ([]gitFile, *object.Commit, error) {
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return nil, nil, err
+	}
+	patterns, err := gitignore.ReadPatterns(worktree.Filesystem, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	ignored := gitignore.NewMatcher(patterns)
+
+	root := worktree.Filesystem.Root()
+	fsys := os.DirFS(root)
+	var files []gitFile
+	err = fs.WalkDir(fsys, ".", func(p string, entry fs.DirEntry, err error) error {
+		if err != nil || p == "." {
+			return err
+		}
+		if entry.IsDir() && entry.Name() == git.GitDirName {
+			return fs.SkipDir
+		}
+		if ignored.Match(strings.Split(p, "/"), entry.IsDir()) || !g.keep(p, entry.IsDir()) {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.Type().IsRegular() {
+			files = append(files, gitFile{path: p, read: func() ([]byte, error) { return fs.ReadFile(fsys, p) }})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	head, err := repo.Head()
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return files, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, nil, err
+	}
+	return files, commit, nil
+}
+
+// commitFiles returns the files of the commit of the ref of the loader, and
+// the commit.
+func (g Git) commitFiles(repo *git.Repository) ([]gitFile, *object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(g.ref))
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolve %q: %w", g.ref, err)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var files []gitFile
+	var walk func(tree *object.Tree, dir string) error
+	walk = func(tree *object.Tree, dir string) error {
+		for _, entry := range tree.Entries {
+			p := path.Join(dir, entry.Name)
+			isDir := entry.Mode == filemode.Dir
+			if !g.keep(p, isDir) {
+				continue
+			}
+			switch {
+			case isDir:
+				subtree, err := tree.Tree(entry.Name)
+				if err != nil {
+					return err
+				}
+				if err := walk(subtree, p); err != nil {
+					return err
+				}
+			case entry.Mode == filemode.Regular || entry.Mode == filemode.Executable:
+				hash := entry.Hash
+				files = append(files, gitFile{path: p, read: func() ([]byte, error) {
+					blob, err := repo.BlobObject(hash)
+					if err != nil {
+						return nil, err
+					}
+					r, err := blob.Reader()
+					if err != nil {
+						return nil, err
+					}
+					defer r.Close()
+					return io.ReadAll(r)
+				}})
+			}
+		}
+		return nil
+	}
+	if err := walk(tree, ""); err != nil {
+		return nil, nil, err
+	}
+	return files, commit, nil
+}
+
+// keep reports whether the file or directory at the slash separated path p
+// passes the path filters of the loader.
+func (g Git) keep(p string, isDir bool) bool {
+	if isDir {
+		for _, name := range g.skipDirs {
+			if path.Base(p) == name {
+				return false
+			}
+		}
+		return !matchAnyGlob(g.exclude, p)
+	}
+	return !matchAnyGlob(g.exclude, p) && (len(g.include) == 0 || matchAnyGlob(g.include, p))
+}
+
+// lastAuthors returns the author of the last commit changing each file, going
+// back in the history of commit until the authors of all the files are found.
+// Merge commits are skipped, so that changes are attributed to the commits of
+// the merged branches.
+func lastAuthors(ctx context.Context, repo *git.Repository, commit *object.Commit, files []gitFile) (map[string]string, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	pending := map[string]bool{}
+	for _, file := range files {
+		if _, err := tree.FindEntry(file.path); err == nil {
+			pending[file.path] = true
+		}
+	}
+
+	authors := make(map[string]string, len(pending))
+	commits, err := repo.Log(&git.LogOptions{From: commit.Hash, Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, err
+	}
+	defer commits.Close()
+	err = commits.ForEach(func(c *object.Commit) error {
+		if len(pending) == 0 {
+			return storer.ErrStop
+		}
+		if c.NumParents() > 1 {
+			return nil
+		}
+		tree, err := c.Tree()
+		if err != nil {
+			return err
+		}
+		var parentTree *object.Tree
+		if c.NumParents() == 1 {
+			parent, err := c.Parent(0)
+			if err != nil {
+				return err
+			}
+			if parentTree, err = parent.Tree(); err != nil {
+				return err
+			}
+		}
+		changes, err := object.DiffTreeContext(ctx, parentTree, tree)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			if name := change.To.Name; pending[name] {
+				authors[name] = fmt.Sprintf("%s <%s>", c.Author.Name, c.Author.Email)
+				delete(pending, name)
+			}
+		}
+		return nil
+	})
+	return authors, err
+}
+
+// isBinary reports whether data is binary: it contains a NUL byte in its first
+// bytes, as git checks, or is not valid UTF-8.
+func isBinary(data []byte) bool {
+	return bytes.IndexByte(data[:min(len(data), _binarySniffLen)], 0) != -1 || !utf8.Valid(data)
+}
+
+// gitLanguage returns the programming or markup language of a file from its
+// name, or an empty string if it is not known.
+func gitLanguage(p string) string {
+	if language, ok := _gitFileNameLanguages[path.Base(p)]; ok {
+		return language
+	}
+	return _gitLanguages[strings.ToLower(path.Ext(p))]
+}
+
+var _gitFileNameLanguages = map[string]string{ //nolint:gochecknoglobals
+	"Dockerfile":     "dockerfile",
+	"Makefile":       "makefile",
+	"CMakeLists.txt": "cmake",
+}
+
+var _gitLanguages = map[string]string{ //nolint:gochecknoglobals
+	".go":    "go",
+	".py":    "python",
+	".js":    "javascript",
+	".jsx":   "javascript",
+	".mjs":   "javascript",
+	".ts":    "typescript",
+	".tsx":   "typescript",
+	".java":  "java",
+	".kt":    "kotlin",
+	".scala": "scala",
+	".rb":    "ruby",
+	".rs":    "rust",
+	".c":     "c",
+	".h":     "c",
+	".cc":    "cpp",
+	".cpp":   "cpp",
+	".hpp":   "cpp",
+	".cs":    "csharp",
+	".php":   "php",
+	".swift": "swift",
+	".lua":   "lua",
+	".sh":    "shell",
+	".bash":  "shell",
+	".sql":   "sql",
+	".proto": "protobuf",
+	".html":  "html",
+	".css":   "css",
+	".md":    "markdown",
+	".rst":   "rst",
+	".tex":   "latex",
+	".sol":   "solidity",
+	".json":  "json",
+	".yaml":  "yaml",
+	".yml":   "yaml",
+	".toml":  "toml",
+	".xml":   "xml",
+}
@@ -0,0 +1,100 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/exp/maps"
+)
+
+// newTestRepository creates a repository with two commits by different
+// authors, and returns its path and the hashes of the commits.
+func newTestRepository(t *testing.T) (string, []string) {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+
+	commit := func(author string, files map[string]string) string {
+		for name, content := range files {
+			p := filepath.Join(dir, filepath.FromSlash(name))
+			require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+			require.NoError(t, os.WriteFile(p, []byte(content), 0o600))
+			_, err := worktree.Add(name)
+			require.NoError(t, err)
+		}
+		hash, err := worktree.Commit("update", &git.CommitOptions{
+			Author: &object.Signature{Name: author, Email: author + "@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+		return hash.String()
+	}
+
+	first := commit("ada", map[string]string{
+		"main.go":             "package main\n",
+		"README.md":           "# Project\n",
+		"vendor/lib/lib.go":   "package lib\n",
+		"assets/logo.png":     "\x89PNG\r\n\x1a\n\x00\x00",
+		".gitignore":          "*.log\n",
+		"docs/guide/intro.md": "Intro\n",
+	})
+	second := commit("grace", map[string]string{"main.go": "package main\n\nfunc main() {}\n"})
+	return dir, []string{first, second}
+}
+
+func TestGitLoader(t *testing.T) {
+	t.Parallel()
+	dir, commits := newTestRepository(t)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "debug.log"), []byte("ignored"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("untracked"), 0o600))
+
+	docs, err := NewGit(dir).Load(context.Background())
+	require.NoError(t, err)
+
+	byPath := map[string]map[string]any{}
+	for _, doc := range docs {
+		byPath[doc.Metadata["path"].(string)] = doc.Metadata
+	}
+	assert.ElementsMatch(t, []string{".gitignore", "README.md", "docs/guide/intro.md", "main.go", "notes.txt"},
+		maps.Keys(byPath))
+
+	assert.Equal(t, map[string]any{
+		"path":        "main.go",
+		"language":    "go",
+		"commit":      commits[1],
+		"last_author": "grace <grace@example.com>",
+	}, byPath["main.go"])
+	assert.Equal(t, "ada <ada@example.com>", byPath["README.md"]["last_author"])
+	assert.Equal(t, "markdown", byPath["README.md"]["language"])
+	assert.NotContains(t, byPath["notes.txt"], "last_author")
+}
+
+func TestGitLoaderWithRef(t *testing.T) {
+	t.Parallel()
+	dir, commits := newTestRepository(t)
+
+	docs, err := NewGit(dir,
+		WithGitRef("HEAD~1"),
+		WithGitIncludeGlobs("*.go", "*.md"),
+		WithGitExcludeGlobs("docs/**"),
+		WithGitSkipDirs(),
+	).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+
+	assert.Equal(t, "README.md", docs[0].Metadata["path"])
+	assert.Equal(t, "main.go", docs[1].Metadata["path"])
+	assert.Equal(t, "package main\n", docs[1].PageContent)
+	assert.Equal(t, commits[0], docs[1].Metadata["commit"])
+	assert.Equal(t, "ada <ada@example.com>", docs[1].Metadata["last_author"])
+	assert.Equal(t, "vendor/lib/lib.go", docs[2].Metadata["path"])
+}
@@ -3,6 +3,7 @@ package documentloaders
 import (
 	"context"
 	"io"
+	"net/url"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -13,39 +14,102 @@ import (
 
 // HTML loads parses and sanitizes html content from an io.Reader.
 type HTML struct {
-	r io.Reader
+	r        io.Reader
+	markdown bool
+	sections bool
 }
 
-var _ Loader = HTML{}
+var _ LazyLoader = HTML{}
 
-// NewHTML creates a new html loader with an io.Reader.
-func NewHTML(r io.Reader) HTML {
-	return HTML{r}
+// HTMLOptions are options for the HTML loader.
+type HTMLOptions func(h *HTML)
+
+// WithMarkdown converts the html to markdown, keeping its headings, lists,
+// tables and links, so that it can be split with a MarkdownTextSplitter. Only
+// the main content of the page is kept: navigation, footers, scripts and other
+// boilerplate are removed. The title, description, canonical URL and language
+// of the page are added to the metadata.
+func WithMarkdown() HTMLOptions {
+	return func(h *HTML) {
+		h.markdown = true
+	}
 }
 
-// Load reads from the io.Reader and returns a single document with the data.
-func (h HTML) Load(_ context.Context) ([]schema.Document, error) {
-	doc, err := goquery.NewDocumentFromReader(h.r)
-	if err != nil {
-		return nil, err
+// WithSections converts the html to markdown as done by WithMarkdown, and
+// returns a document for every section of the page, starting at each heading.
+// The heading of the section is set as "section" in the metadata, and the
+// headings of the section and its parents, separated by " > ", as
+// "section_path".
+func WithSections() HTMLOptions {
+	return func(h *HTML) {
+		h.markdown = true
+		h.sections = true
 	}
+}
 
-	var sel *goquery.Selection
-	if doc.Has("body") != nil {
-		sel = doc.Find("body").Contents()
-	} else {
-		sel = doc.Contents()
+// NewHTML creates a new html loader with an io.Reader.
+func NewHTML(r io.Reader, opts ...HTMLOptions) HTML {
+	h := HTML{r: r}
+	for _, opt := range opts {
+		opt(&h)
 	}
+	return h
+}
+
+// Load reads from the io.Reader and returns a single document with the data, or
+// a document per section if WithSections is used.
+func (h HTML) Load(ctx context.Context) ([]schema.Document, error) {
+	return collectDocuments(ctx, h.LazyLoad(ctx))
+}
 
-	sanitized := bluemonday.UGCPolicy().Sanitize(sel.Text())
-	pagecontent := strings.TrimSpace(sanitized)
+// LazyLoad reads from the io.Reader and sends the documents returned by Load.
+func (h HTML) LazyLoad(ctx context.Context) <-chan DocumentResult {
+	return lazyLoad(ctx, func(yield func(schema.Document) bool) error {
+		doc, err := goquery.NewDocumentFromReader(h.r)
+		if err != nil {
+			return err
+		}
 
-	return []schema.Document{
-		{
+		if h.markdown {
+			h.yieldMarkdown(doc, yield)
+			return nil
+		}
+
+		var sel *goquery.Selection
+		if doc.Has("body") != nil {
+			sel = doc.Find("body").Contents()
+		} else {
+			sel = doc.Contents()
+		}
+
+		sanitized := bluemonday.UGCPolicy().Sanitize(sel.Text())
+		pagecontent := strings.TrimSpace(sanitized)
+
+		yield(schema.Document{
 			PageContent: pagecontent,
 			Metadata:    map[string]any{},
-		},
-	}, nil
+		})
+		return nil
+	})
+}
+
+func (h HTML) yieldMarkdown(doc *goquery.Document, yield func(schema.Document) bool) {
+	metadata := htmlMetadata(doc)
+	var base *url.URL
+	if canonical, ok := metadata["canonical_url"].(string); ok {
+		base, _ = url.Parse(canonical)
+	}
+	markdown := htmlToMarkdown(doc, base)
+
+	if !h.sections {
+		yield(schema.Document{PageContent: markdown, Metadata: metadata})
+		return
+	}
+	for _, section := range splitMarkdownSections(markdown, metadata) {
+		if !yield(section) {
+			return
+		}
+	}
 }
 
 // LoadAndSplit reads text data from the io.Reader and splits it into multiple
@@ -0,0 +1,326 @@
+package documentloaders
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// _boilerplateSelector selects the elements that are not part of the content of
+// a page and are skipped when converting it to markdown.
+const _boilerplateSelector = "script, style, noscript, template, iframe, svg, form, nav, footer, aside, " +
+	"[role=navigation], [role=contentinfo], [role=banner], [aria-hidden=true]"
+
+var (
+	_whitespaceRegexp = regexp.MustCompile(`\s+`)
+	_newlinesRegexp   = regexp.MustCompile(`\n{3,}`)
+	_headingRegexp    = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+)
+
+// htmlMetadata returns the title, description, canonical URL and language of
+// an html document.
+func htmlMetadata(doc *goquery.Document) map[string]any {
+	metadata := map[string]any{}
+	set := func(key, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			if _, ok := metadata[key]; !ok {
+				metadata[key] = value
+			}
+		}
+	}
+
+	set("title", doc.Find("head title").First().Text())
+	set("title", doc.Find(`meta[property="og:title"]`).AttrOr("content", ""))
+	set("description", doc.Find(`meta[name="description"]`).AttrOr("content", ""))
+	set("description", doc.Find(`meta[property="og:description"]`).AttrOr("content", ""))
+	set("canonical_url", doc.Find(`link[rel="canonical"]`).AttrOr("href", ""))
+	set("canonical_url", doc.Find(`meta[property="og:url"]`).AttrOr("content", ""))
+	set("language", doc.Find("html").AttrOr("lang", ""))
+	return metadata
+}
+
+// htmlToMarkdown converts the main content of an html document to markdown,
+// keeping headings, lists, tables, links and code blocks. Relative links are
+// resolved against base if it is not nil.
+func htmlToMarkdown(doc *goquery.Document, base *url.URL) string {
+	root := doc.Find("main").First()
+	if root.Length() == 0 {
+		root = doc.Find("article").First()
+	}
+	if root.Length() == 0 {
+		root = doc.Find("body").First()
+	}
+	if root.Length() == 0 {
+		root = doc.Selection
+	}
+	root.Find(_boilerplateSelector).Remove()
+
+	w := &markdownWriter{base: base}
+	w.children(root)
+	return cleanMarkdown(w.b.String())
+}
+
+// markdownWriter writes html elements as markdown.
+type markdownWriter struct {
+	b    strings.Builder
+	base *url.URL
+}
+
+func (w *markdownWriter) children(sel *goquery.Selection) {
+	sel.Contents().Each(func(_ int, child *goquery.Selection) {
+		w.node(child)
+	})
+}
+
+func (w *markdownWriter) node(sel *goquery.Selection) { //nolint:cyclop,funlen
+	switch name := goquery.NodeName(sel); name {
+	case "#text":
+		w.text(sel.Text())
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		text := inlineMarkdown(sel, w.base)
+		if text != "" {
+			w.block(strings.Repeat("#", int(name[1]-'0')) + " " + text)
+		}
+	case "p", "div", "section", "article", "main", "header", "figure", "figcaption", "dl", "dt", "dd", "address":
+		w.b.WriteString("\n\n")
+		w.children(sel)
+		w.b.WriteString("\n\n")
+	case "br":
+		w.b.WriteString("\n")
+	case "hr":
+		w.block("---")
+	case "ul", "ol":
+		w.block(listMarkdown(sel, w.base))
+	case "pre":
+		w.block("```\n" + strings.Trim(sel.Text(), "\n") + "\n```")
+	case "blockquote":
+		inner := &markdownWriter{base: w.base}
+		inner.children(sel)
+		lines := strings.Split(cleanMarkdown(inner.b.String()), "\n")
+		for i, line := range lines {
+			lines[i] = strings.TrimRight("> "+line, " ")
+		}
+		w.block(strings.Join(lines, "\n"))
+	case "table":
+		w.block(tableMarkdown(sel, w.base))
+	case "a", "strong", "b", "em", "i", "code", "img":
+		w.inline(inlineMarkdown(sel, w.base))
+	default:
+		w.children(sel)
+	}
+}
+
+// block writes a block of markdown, separated from the text around it by blank
+// lines.
+func (w *markdownWriter) block(s string) {
+	if s == "" {
+		return
+	}
+	w.b.WriteString("\n\n")
+	w.b.WriteString(s)
+	w.b.WriteString("\n\n")
+}
+
+// text writes text, collapsing its whitespace.
+func (w *markdownWriter) text(s string) {
+	w.inline(_whitespaceRegexp.ReplaceAllString(s, " "))
+}
+
+func (w *markdownWriter) inline(s string) {
+	if current := w.b.String(); current == "" || strings.HasSuffix(current, "\n") {
+		s = strings.TrimLeft(s, " ")
+	}
+	w.b.WriteString(s)
+}
+
+// inlineMarkdown returns the markdown of an inline element or of the contents
+// of a block element, on a single line.
+func inlineMarkdown(sel *goquery.Selection, base *url.URL) string {
+	var b strings.Builder
+	var write func(s *goquery.Selection)
+	write = func(s *goquery.Selection) {
+		s.Contents().Each(func(_ int, child *goquery.Selection) {
+			b.WriteString(inlineMarkdown(child, base))
+		})
+	}
+
+	switch goquery.NodeName(sel) {
+	case "#text":
+		return _whitespaceRegexp.ReplaceAllString(sel.Text(), " ")
+	case "br":
+		return " "
+	case "a":
+		write(sel)
+		text := strings.TrimSpace(b.String())
+		href := resolveLink(sel.AttrOr("href", ""), base)
+		if href == "" || text == "" {
+			return text
+		}
+		return fmt.Sprintf("[%s](%s)", text, href)
+	case "strong", "b":
+		return wrapInline(sel, base, "**")
+	case "em", "i":
+		return wrapInline(sel, base, "*")
+	case "code":
+		return wrapInline(sel, base, "`")
+	case "img":
+		src := resolveLink(sel.AttrOr("src", ""), base)
+		if src == "" {
+			return ""
+		}
+		return fmt.Sprintf("![%s](%s)", strings.TrimSpace(sel.AttrOr("alt", "")), src)
+	default:
+		write(sel)
+		return strings.TrimSpace(_whitespaceRegexp.ReplaceAllString(b.String(), " "))
+	}
+}
+
+func wrapInline(sel *goquery.Selection, base *url.URL, marker string) string {
+	var b strings.Builder
+	sel.Contents().Each(func(_ int, child *goquery.Selection) {
+		b.WriteString(inlineMarkdown(child, base))
+	})
+	text := strings.TrimSpace(b.String())
+	if text == "" {
+		return ""
+	}
+	return marker + text + marker
+}
+
+// listMarkdown returns the markdown of a list, indenting nested lists.
+func listMarkdown(sel *goquery.Selection, base *url.URL) string {
+	ordered := goquery.NodeName(sel) == "ol"
+	var lines []string
+	n := 0
+	sel.ChildrenFiltered("li").Each(func(_ int, item *goquery.Selection) {
+		n++
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", n)
+		}
+
+		var text strings.Builder
+		var nested []string
+		item.Contents().Each(func(_ int, child *goquery.Selection) {
+			switch goquery.NodeName(child) {
+			case "ul", "ol":
+				for _, line := range strings.Split(listMarkdown(child, base), "\n") {
+					nested = append(nested, strings.Repeat(" ", len(marker))+line)
+				}
+			default:
+				text.WriteString(inlineMarkdown(child, base))
+				text.WriteString(" ")
+			}
+		})
+		lines = append(lines, marker+strings.TrimSpace(_whitespaceRegexp.ReplaceAllString(text.String(), " ")))
+		lines = append(lines, nested...)
+	})
+	return strings.Join(lines, "\n")
+}
+
+// tableMarkdown returns the markdown of a table, using its first row as header.
+func tableMarkdown(sel *goquery.Selection, base *url.URL) string {
+	var rows [][]string
+	sel.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+		var cells []string
+		tr.ChildrenFiltered("th, td").Each(func(_ int, cell *goquery.Selection) {
+			cells = append(cells, inlineMarkdown(cell, base))
+		})
+		rows = append(rows, cells)
+	})
+	if len(rows) == 0 {
+		return ""
+	}
+	return markdownTable(rows)
+}
+
+// resolveLink returns href resolved against base, or an empty string if it is
+// not a web or mail link.
+func resolveLink(href string, base *url.URL) string {
+	u, err := url.Parse(strings.TrimSpace(href))
+	if err != nil || href == "" || strings.HasPrefix(href, "#") {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	switch u.Scheme {
+	case "", "http", "https", "mailto":
+		return u.String()
+	default:
+		return ""
+	}
+}
+
+// cleanMarkdown trims the lines of markdown and removes extra blank lines.
+func cleanMarkdown(markdown string) string {
+	lines := strings.Split(markdown, "\n")
+	inCode := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			lines[i] = strings.TrimSpace(line)
+			continue
+		}
+		if !inCode {
+			lines[i] = strings.TrimRight(line, " \t")
+		}
+	}
+	return strings.TrimSpace(_newlinesRegexp.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// splitMarkdownSections splits markdown into a document per section, starting
+// at each heading. The title of the section is set as "section" in the
+// metadata, and the titles of the section and its parents, separated by " > ",
+// as "section_path".
+func splitMarkdownSections(markdown string, metadata map[string]any) []schema.Document {
+	var docs []schema.Document
+	var headings []string
+	var current []string
+	var section, path string
+
+	flush := func() {
+		content := strings.TrimSpace(strings.Join(current, "\n"))
+		current = nil
+		if content == "" {
+			return
+		}
+		docMetadata := make(map[string]any, len(metadata)+2)
+		for k, v := range metadata {
+			docMetadata[k] = v
+		}
+		if section != "" {
+			docMetadata["section"] = section
+			docMetadata["section_path"] = path
+		}
+		docs = append(docs, schema.Document{PageContent: content, Metadata: docMetadata})
+	}
+
+	inCode := false
+	for _, line := range strings.Split(markdown, "\n") {
+		if strings.HasPrefix(line, "```") {
+			inCode = !inCode
+		}
+		match := _headingRegexp.FindStringSubmatch(line)
+		if inCode || match == nil {
+			current = append(current, line)
+			continue
+		}
+
+		flush()
+		level := len(match[1])
+		if len(headings) >= level {
+			headings = headings[:level-1]
+		}
+		headings = append(headings, match[2])
+		section = match[2]
+		path = strings.Join(headings, " > ")
+		current = append(current, line)
+	}
+	flush()
+	return docs
+}
@@ -48,3 +48,47 @@ func TestHTMLLoader(t *testing.T) {
 	expectedMetadata := map[string]any{}
 	assert.Equal(t, expectedMetadata, docs[0].Metadata)
 }
+
+func TestHTMLLoaderMarkdown(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/article.html")
+	require.NoError(t, err)
+
+	docs, err := NewHTML(file, WithMarkdown()).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+
+	expected := "# Gardening basics\n\n" +
+		"Start **small**, with a [few tools](https://example.com/guides/tools).\n\n" +
+		"## Soil\n\n" +
+		"Test the soil first.\n\n" +
+		"- Compost\n  - Leaves\n- Mulch\n\n" +
+		"## Planting\n\n" +
+		"| Plant | Month |\n| --- | --- |\n| Tomato | May |\n\n" +
+		"```\nwater daily\nweed weekly\n```"
+	assert.Equal(t, expected, docs[0].PageContent)
+	assert.Equal(t, map[string]any{
+		"title":         "Gardening basics",
+		"description":   "How to start a vegetable garden.",
+		"canonical_url": "https://example.com/guides/gardening",
+		"language":      "en",
+	}, docs[0].Metadata)
+}
+
+func TestHTMLLoaderSections(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/article.html")
+	require.NoError(t, err)
+
+	docs, err := NewHTML(file, WithSections()).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 3)
+
+	paths := make([]any, 0, len(docs))
+	for _, doc := range docs {
+		paths = append(paths, doc.Metadata["section_path"])
+		assert.Equal(t, "Gardening basics", doc.Metadata["title"])
+	}
+	assert.Equal(t, []any{"Gardening basics", "Gardening basics > Soil", "Gardening basics > Planting"}, paths)
+	assert.Equal(t, "## Soil\n\nTest the soil first.\n\n- Compost\n  - Leaves\n- Mulch", docs[1].PageContent)
+}
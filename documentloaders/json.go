@@ -0,0 +1,325 @@
+package documentloaders
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"golang.org/x/exp/maps"
+	"golang.org/x/exp/slices"
+)
+
+// ErrInvalidJSONPath is returned when a selector of the JSON loader is not a
+// valid path.
+var ErrInvalidJSONPath = errors.New("invalid JSON path")
+
+// JSON loads documents from JSON or JSON Lines data from an io.Reader. The
+// values of the data are read one at a time, so that large JSON Lines files
+// are streamed.
+type JSON struct {
+	r              io.Reader
+	records        string
+	content        string
+	metadataFields []string
+}
+
+var _ LazyLoader = JSON{}
+
+// JSONOptions are options for the JSON loader.
+type JSONOptions func(j *JSON)
+
+// WithRecordSelector sets the path selecting the records of each JSON value,
+// each record being loaded as a document. Paths use a JSONPath-like syntax
+// made of fields, indexes and wildcards, such as "$.messages[*]" or
+// "$.data['items'][*]". The default path, "$", loads each JSON value, or each
+// line of JSON Lines data, as a document.
+func WithRecordSelector(path string) JSONOptions {
+	return func(j *JSON) {
+		j.records = path
+	}
+}
+
+// WithContentSelector sets the path, relative to a record, of the content of
+// the document. Strings are used as is, other values are encoded as JSON. By
+// default, the whole record is used. Records without content are skipped.
+func WithContentSelector(path string) JSONOptions {
+	return func(j *JSON) {
+		j.content = path
+	}
+}
+
+// WithMetadataFields sets the paths, relative to a record, of the values added
+// to the metadata of the document. The key of a value is the last field of its
+// path.
+func WithMetadataFields(paths ...string) JSONOptions {
+	return func(j *JSON) {
+		j.metadataFields = paths
+	}
+}
+
+// NewJSON creates a new JSON and JSON Lines loader with an io.Reader.
+func NewJSON(r io.Reader, opts ...JSONOptions) JSON {
+	j := JSON{r: r, records: "$", content: "$"}
+	for _, opt := range opts {
+		opt(&j)
+	}
+	return j
+}
+
+// Load reads from the io.Reader and returns a document for every record. The
+// number of the record, starting at 1, is set as "seq_num" in the metadata.
+func (j JSON) Load(ctx context.Context) ([]schema.Document, error) {
+	return collectDocuments(ctx, j.LazyLoad(ctx))
+}
+
+// LazyLoad reads from the io.Reader and sends a document for every record as
+// it is read.
+func (j JSON) LazyLoad(ctx context.Context) <-chan DocumentResult {
+	return lazyLoad(ctx, func(yield func(schema.Document) bool) error {
+		records, err := parseJSONPath(j.records)
+		if err != nil {
+			return err
+		}
+		content, err := parseJSONPath(j.content)
+		if err != nil {
+			return err
+		}
+		metadataFields := make([]jsonPath, len(j.metadataFields))
+		for i, field := range j.metadataFields {
+			if metadataFields[i], err = parseJSONPath(field); err != nil {
+				return err
+			}
+		}
+
+		decoder := json.NewDecoder(j.r)
+		decoder.UseNumber()
+		seq := 0
+		for {
+			var value any
+			err := decoder.Decode(&value)
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			for _, record := range records.selectValues(value) {
+				doc, ok, err := jsonDocument(record, content, metadataFields)
+				if err != nil {
+					return err
+				}
+				if !ok {
+					continue
+				}
+				seq++
+				doc.Metadata["seq_num"] = seq
+				if !yield(doc) {
+					return nil
+				}
+			}
+		}
+	})
+}
+
+// LoadAndSplit reads from the io.Reader and splits the documents using a text
+// splitter.
+func (j JSON) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := j.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+// jsonDocument returns the document of a record, and false if the record has
+// no content.
+func jsonDocument(record any, content jsonPath, metadataFields []jsonPath) (schema.Document, bool, error) {
+	values := content.selectValues(record)
+	if len(values) == 0 || values[0] == nil {
+		return schema.Document{}, false, nil
+	}
+
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		text, err := jsonText(value)
+		if err != nil {
+			return schema.Document{}, false, err
+		}
+		parts = append(parts, text)
+	}
+
+	metadata := map[string]any{}
+	for _, field := range metadataFields {
+		values := field.selectValues(record)
+		switch len(values) {
+		case 0:
+			continue
+		case 1:
+			metadata[field.key()] = jsonMetadataValue(values[0])
+		default:
+			list := make([]any, len(values))
+			for i, value := range values {
+				list[i] = jsonMetadataValue(value)
+			}
+			metadata[field.key()] = list
+		}
+	}
+
+	return schema.Document{PageContent: strings.Join(parts, "\n"), Metadata: metadata}, true, nil
+}
+
+// jsonText returns strings as is and encodes other values as JSON.
+func jsonText(value any) (string, error) {
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	return string(data), err
+}
+
+// jsonMetadataValue converts numbers to int64 or float64, and objects and
+// arrays to JSON.
+func jsonMetadataValue(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case map[string]any, []any:
+		text, _ := jsonText(v)
+		return text
+	default:
+		return v
+	}
+}
+
+// jsonPathStep is a step of a JSON path: a field, an index, or a wildcard
+// matching all the elements of an array or values of an object.
+type jsonPathStep struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+type jsonPath []jsonPathStep
+
+// parseJSONPath parses a path such as "$.a.b[0]['c'][*]". The leading "$" is
+// optional, so that "a.b" is the same path as "$.a.b".
+func parseJSONPath(path string) (jsonPath, error) { //nolint:cyclop
+	rest := strings.TrimPrefix(strings.TrimSpace(path), "$")
+	if rest != "" && rest[0] != '.' && rest[0] != '[' {
+		rest = "." + rest
+	}
+	var steps jsonPath
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, "[*]"):
+			steps = append(steps, jsonPathStep{wildcard: true})
+			rest = rest[len("[*]"):]
+		case strings.HasPrefix(rest, ".*"):
+			steps = append(steps, jsonPathStep{wildcard: true})
+			rest = rest[len(".*"):]
+		case rest[0] == '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end == -1 {
+				end = len(rest) - 1
+			}
+			field := rest[1 : end+1]
+			if field == "" {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidJSONPath, path)
+			}
+			steps = append(steps, jsonPathStep{field: field})
+			rest = rest[end+1:]
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end == -1 {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidJSONPath, path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			if unquoted, ok := unquoteJSONPathField(inner); ok {
+				steps = append(steps, jsonPathStep{field: unquoted})
+			} else if index, err := strconv.Atoi(inner); err == nil {
+				steps = append(steps, jsonPathStep{index: index, isIndex: true})
+			} else {
+				return nil, fmt.Errorf("%w: %q", ErrInvalidJSONPath, path)
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, fmt.Errorf("%w: %q", ErrInvalidJSONPath, path)
+		}
+	}
+	return steps, nil
+}
+
+func unquoteJSONPathField(s string) (string, bool) {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], true
+	}
+	return "", false
+}
+
+// selectValues returns the values matched by the path in value.
+func (p jsonPath) selectValues(value any) []any {
+	values := []any{value}
+	for _, step := range p {
+		var next []any
+		for _, v := range values {
+			next = append(next, step.apply(v)...)
+		}
+		values = next
+	}
+	return values
+}
+
+func (s jsonPathStep) apply(value any) []any {
+	switch v := value.(type) {
+	case map[string]any:
+		if s.wildcard {
+			keys := maps.Keys(v)
+			slices.Sort(keys)
+			values := make([]any, 0, len(v))
+			for _, key := range keys {
+				values = append(values, v[key])
+			}
+			return values
+		}
+		if item, ok := v[s.field]; ok && !s.isIndex {
+			return []any{item}
+		}
+	case []any:
+		if s.wildcard {
+			return v
+		}
+		if s.isIndex {
+			index := s.index
+			if index < 0 {
+				index += len(v)
+			}
+			if index >= 0 && index < len(v) {
+				return []any{v[index]}
+			}
+		}
+	}
+	return nil
+}
+
+// key returns the metadata key of the values selected by the path: its last
+// field.
+func (p jsonPath) key() string {
+	for i := len(p) - 1; i >= 0; i-- {
+		if p[i].field != "" {
+			return p[i].field
+		}
+	}
+	return "value"
+}
@@ -0,0 +1,92 @@
+package documentloaders
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONLoader(t *testing.T) {
+	t.Parallel()
+
+	data := `{
+	"channel": "support",
+	"messages": [
+		{"id": 1, "author": {"name": "Ada"}, "text": "The build fails.", "tags": ["ci", "urgent"]},
+		{"id": 2, "author": {"name": "Bob"}, "text": "Fixed in main.", "score": 0.5},
+		{"id": 3, "author": {"name": "Eve"}}
+	]
+}`
+	loader := NewJSON(strings.NewReader(data),
+		WithRecordSelector("$.messages[*]"),
+		WithContentSelector(".text"),
+		WithMetadataFields("$.id", "$.author['name']", "$.score", "$.tags[*]"),
+	)
+
+	docs, err := loader.Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "The build fails.", docs[0].PageContent)
+	assert.Equal(t, map[string]any{
+		"id": int64(1), "name": "Ada", "tags": []any{"ci", "urgent"}, "seq_num": 1,
+	}, docs[0].Metadata)
+	assert.Equal(t, "Fixed in main.", docs[1].PageContent)
+	assert.Equal(t, map[string]any{"id": int64(2), "name": "Bob", "score": 0.5, "seq_num": 2}, docs[1].Metadata)
+}
+
+func TestJSONLoaderLines(t *testing.T) {
+	t.Parallel()
+
+	data := "{\"q\": \"What is Go?\", \"a\": {\"text\": \"A language.\"}}\n\n{\"q\": \"Who made it?\", \"a\": [1, 2]}\n"
+	docs, err := NewJSON(strings.NewReader(data), WithContentSelector("$..a")).Load(context.Background())
+	require.ErrorIs(t, err, ErrInvalidJSONPath)
+	assert.Empty(t, docs)
+
+	docs, err = NewJSON(strings.NewReader(data), WithContentSelector("a"), WithMetadataFields(".q")).
+		Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+	assert.Equal(t, `{"text":"A language."}`, docs[0].PageContent)
+	assert.Equal(t, map[string]any{"q": "What is Go?", "seq_num": 1}, docs[0].Metadata)
+	assert.Equal(t, `[1,2]`, docs[1].PageContent)
+
+	_, err = NewJSON(strings.NewReader(`{"a": `)).Load(context.Background())
+	require.Error(t, err)
+}
+
+func TestParseJSONPath(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]any{
+		"a": []any{
+			map[string]any{"b c": "first"},
+			map[string]any{"b c": "second"},
+		},
+		"d": map[string]any{"y": 2, "x": 1},
+	}
+	tests := map[string][]any{
+		"$":              {value},
+		"":               {value},
+		"$.a[0]['b c']":  {"first"},
+		`$.a[-1]["b c"]`: {"second"},
+		"$.a[*]['b c']":  {"first", "second"},
+		"$.d.*":          {1, 2},
+		"$.missing":      nil,
+		"$.a[5]":         nil,
+		"d.x":            {1},
+	}
+	for path, expected := range tests {
+		p, err := parseJSONPath(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, expected, p.selectValues(value), path)
+	}
+
+	for _, path := range []string{"$..a", "$.", "$[0", "$[x]"} {
+		_, err := parseJSONPath(path)
+		require.ErrorIs(t, err, ErrInvalidJSONPath, path)
+	}
+}
@@ -0,0 +1,163 @@
+package documentloaders
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ErrMissingPart is returned when a part required by a loader is missing from
+// an Office Open XML or EPUB archive.
+var ErrMissingPart = errors.New("missing part in archive")
+
+// _maxPartSize is the maximum size of a part read from an archive.
+const _maxPartSize = 256 << 20
+
+// zipArchive is an Office Open XML or EPUB archive.
+type zipArchive struct {
+	files map[string]*zip.File
+}
+
+func openZipArchive(r io.ReaderAt, size int64) (*zipArchive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	a := &zipArchive{files: make(map[string]*zip.File, len(zr.File))}
+	for _, f := range zr.File {
+		a.files[strings.TrimPrefix(f.Name, "/")] = f
+	}
+	return a, nil
+}
+
+// has reports whether the archive contains the named part.
+func (a *zipArchive) has(name string) bool {
+	_, ok := a.files[name]
+	return ok
+}
+
+// read returns the contents of the named part.
+func (a *zipArchive) read(name string) ([]byte, error) {
+	f, ok := a.files[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMissingPart, name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(io.LimitReader(rc, _maxPartSize))
+}
+
+// decoder returns an xml decoder for the named part.
+func (a *zipArchive) decoder(name string) (*xml.Decoder, func() error, error) {
+	f, ok := a.files[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("%w: %s", ErrMissingPart, name)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, nil, err
+	}
+	return xml.NewDecoder(io.LimitReader(rc, _maxPartSize)), rc.Close, nil
+}
+
+// unmarshal decodes the named xml part into v.
+func (a *zipArchive) unmarshal(name string, v any) error {
+	data, err := a.read(name)
+	if err != nil {
+		return err
+	}
+	return xml.Unmarshal(data, v)
+}
+
+// ooxmlRelationships are the relationships of an Office Open XML part.
+type ooxmlRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Type   string `xml:"Type,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+// relationships returns the targets of the relationships of the named part by
+// id, resolved to part names, and the ids of the relationships by type suffix.
+func (a *zipArchive) relationships(part string) (map[string]string, map[string]string, error) {
+	dir, file := path.Split(part)
+	var rels ooxmlRelationships
+	if err := a.unmarshal(path.Join(dir, "_rels", file+".rels"), &rels); err != nil {
+		return nil, nil, err
+	}
+
+	targets := make(map[string]string, len(rels.Relationships))
+	byType := make(map[string]string, len(rels.Relationships))
+	for _, rel := range rels.Relationships {
+		target := rel.Target
+		if strings.HasPrefix(target, "/") {
+			target = strings.TrimPrefix(target, "/")
+		} else {
+			target = path.Join(dir, target)
+		}
+		targets[rel.ID] = target
+		byType[path.Base(rel.Type)] = rel.ID
+	}
+	return targets, byType, nil
+}
+
+// coreTitle returns the title in the core properties of an Office Open XML
+// archive, if any.
+func (a *zipArchive) coreTitle() string {
+	var core struct {
+		Title string `xml:"title"`
+	}
+	if err := a.unmarshal("docProps/core.xml", &core); err != nil {
+		return ""
+	}
+	return strings.TrimSpace(core.Title)
+}
+
+// attr returns the value of the attribute of an element with the given local
+// name.
+func attr(el xml.StartElement, local string) string {
+	for _, a := range el.Attr {
+		if a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// markdownTable formats rows as a markdown table, using the first row as
+// header.
+func markdownTable(rows [][]string) string {
+	columns := 0
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+	if columns == 0 {
+		return ""
+	}
+
+	formatRow := func(cells []string) string {
+		padded := make([]string, columns)
+		for i, cell := range cells {
+			padded[i] = strings.ReplaceAll(strings.ReplaceAll(cell, "|", `\|`), "\n", " ")
+		}
+		return "| " + strings.Join(padded, " | ") + " |"
+	}
+	separator := make([]string, columns)
+	for i := range separator {
+		separator[i] = "---"
+	}
+
+	lines := []string{formatRow(rows[0]), "| " + strings.Join(separator, " | ") + " |"}
+	for _, row := range rows[1:] {
+		lines = append(lines, formatRow(row))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -16,7 +16,7 @@ type PDF struct {
 	password string
 }
 
-var _ Loader = PDF{}
+var _ LazyLoader = PDF{}
 
 // PDFOptions are options for the PDF loader.
 type PDFOptions func(pdf *PDF)
@@ -51,54 +51,65 @@ func (p *PDF) getPassword() string {
 
 // Load reads from the io.Reader for the PDF data and returns the documents with the data and with
 // metadata attached of the page number and total number of pages of the PDF.
-func (p PDF) Load(_ context.Context) ([]schema.Document, error) {
-	var reader *pdf.Reader
-	var err error
-
-	if p.password != "" {
-		reader, err = pdf.NewReaderEncrypted(p.r, p.s, p.getPassword)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		reader, err = pdf.NewReader(p.r, p.s)
-		if err != nil {
-			return nil, err
-		}
+func (p PDF) Load(ctx context.Context) ([]schema.Document, error) {
+	docs, err := collectDocuments(ctx, p.LazyLoad(ctx))
+	if docs == nil && err == nil {
+		docs = []schema.Document{}
 	}
+	return docs, err
+}
 
-	numPages := reader.NumPage()
-
-	docs := []schema.Document{}
-
-	// fonts to be used when getting plain text from pages
-	fonts := make(map[string]*pdf.Font)
-	for i := 1; i < numPages+1; i++ {
-		p := reader.Page(i)
-		// add fonts to map
-		for _, name := range p.Fonts() {
-			// only add the font if we don't already have it
-			if _, ok := fonts[name]; !ok {
-				f := p.Font(name)
-				fonts[name] = &f
+// LazyLoad reads from the io.Reader for the PDF data and sends a document for every page as it is
+// read, with the same metadata as Load.
+func (p PDF) LazyLoad(ctx context.Context) <-chan DocumentResult {
+	return lazyLoad(ctx, func(yield func(schema.Document) bool) error {
+		var reader *pdf.Reader
+		var err error
+
+		if p.password != "" {
+			reader, err = pdf.NewReaderEncrypted(p.r, p.s, p.getPassword)
+			if err != nil {
+				return err
+			}
+		} else {
+			reader, err = pdf.NewReader(p.r, p.s)
+			if err != nil {
+				return err
 			}
-		}
-		text, err := p.GetPlainText(fonts)
-		if err != nil {
-			return nil, err
 		}
 
-		// add the document to the doc list
-		docs = append(docs, schema.Document{
-			PageContent: text,
-			Metadata: map[string]any{
-				"page":        i,
-				"total_pages": numPages,
-			},
-		})
-	}
+		numPages := reader.NumPage()
+
+		// fonts to be used when getting plain text from pages
+		fonts := make(map[string]*pdf.Font)
+		for i := 1; i < numPages+1; i++ {
+			p := reader.Page(i)
+			// add fonts to map
+			for _, name := range p.Fonts() {
+				// only add the font if we don't already have it
+				if _, ok := fonts[name]; !ok {
+					f := p.Font(name)
+					fonts[name] = &f
+				}
+			}
+			text, err := p.GetPlainText(fonts)
+			if err != nil {
+				return err
+			}
 
-	return docs, nil
+			// send the document for the page
+			if !yield(schema.Document{
+				PageContent: text,
+				Metadata: map[string]any{
+					"page":        i,
+					"total_pages": numPages,
+				},
+			}) {
+				return nil
+			}
+		}
+		return nil
+	})
 }
 
 // LoadAndSplit reads pdf data from the io.Reader and splits it into multiple
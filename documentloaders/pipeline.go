@@ -0,0 +1,122 @@
+package documentloaders
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// _defaultBatchSize is the default number of documents added to the vector
+// store at once by LoadIntoVectorStore.
+const _defaultBatchSize = 100
+
+// PipelineOption is a function for configuring LoadIntoVectorStore.
+type PipelineOption func(p *pipeline)
+
+type pipeline struct {
+	splitter     textsplitter.TextSplitter
+	batchSize    int
+	storeOptions []vectorstores.Option
+}
+
+// WithSplitter sets the text splitter used to split the loaded documents before
+// adding them to the vector store. By default, documents are not split.
+func WithSplitter(splitter textsplitter.TextSplitter) PipelineOption {
+	return func(p *pipeline) {
+		p.splitter = splitter
+	}
+}
+
+// WithBatchSize sets the maximum number of documents added to the vector store
+// at once.
+func WithBatchSize(size int) PipelineOption {
+	return func(p *pipeline) {
+		p.batchSize = size
+	}
+}
+
+// WithVectorStoreOptions sets the options passed to AddDocuments.
+func WithVectorStoreOptions(options ...vectorstores.Option) PipelineOption {
+	return func(p *pipeline) {
+		p.storeOptions = options
+	}
+}
+
+// LoadIntoVectorStore loads the documents of loader, splits them and adds them
+// to store in batches, returning the ids of the added documents. If loader is a
+// LazyLoader, documents are streamed so that only one batch is held in memory.
+func LoadIntoVectorStore(
+	ctx context.Context,
+	loader Loader,
+	store vectorstores.VectorStore,
+	options ...PipelineOption,
+) ([]string, error) {
+	p := pipeline{batchSize: _defaultBatchSize}
+	for _, opt := range options {
+		opt(&p)
+	}
+	if p.batchSize <= 0 {
+		p.batchSize = 1
+	}
+
+	lazyLoader, ok := loader.(LazyLoader)
+	if !ok {
+		docs, err := loader.Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return p.add(ctx, store, docs)
+	}
+
+	// Stop the loader if adding documents fails.
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var ids []string
+	batch := make([]schema.Document, 0, p.batchSize)
+	for result := range lazyLoader.LazyLoad(ctx) {
+		if result.Err != nil {
+			return ids, result.Err
+		}
+		batch = append(batch, result.Document)
+		if len(batch) < p.batchSize {
+			continue
+		}
+		batchIDs, err := p.add(ctx, store, batch)
+		ids = append(ids, batchIDs...)
+		if err != nil {
+			return ids, err
+		}
+		batch = batch[:0]
+	}
+	if err := ctx.Err(); err != nil {
+		return ids, err
+	}
+
+	batchIDs, err := p.add(ctx, store, batch)
+	return append(ids, batchIDs...), err
+}
+
+// add splits docs and adds them to the store in batches.
+func (p pipeline) add(ctx context.Context, store vectorstores.VectorStore, docs []schema.Document) ([]string, error) {
+	if p.splitter != nil {
+		var err error
+		docs, err = textsplitter.SplitDocuments(p.splitter, docs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var ids []string
+	for start := 0; start < len(docs); start += p.batchSize {
+		end := min(start+p.batchSize, len(docs))
+		batchIDs, err := store.AddDocuments(ctx, docs[start:end], p.storeOptions...)
+		ids = append(ids, batchIDs...)
+		if err != nil {
+			return ids, err
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,98 @@
+package documentloaders
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// batchStore is a vector store recording the batches of documents added.
+type batchStore struct {
+	batches [][]schema.Document
+}
+
+func (s *batchStore) AddDocuments(_ context.Context, docs []schema.Document, _ ...vectorstores.Option) ([]string, error) {
+	s.batches = append(s.batches, append([]schema.Document{}, docs...))
+	ids := make([]string, len(docs))
+	for i := range docs {
+		ids[i] = fmt.Sprintf("%d-%d", len(s.batches), i)
+	}
+	return ids, nil
+}
+
+func (s *batchStore) SimilaritySearch(context.Context, string, int, ...vectorstores.Option) ([]schema.Document, error) {
+	return nil, nil
+}
+
+func TestLazyLoad(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/test.csv")
+	require.NoError(t, err)
+
+	rows := 0
+	for result := range NewCSV(file).LazyLoad(context.Background()) {
+		require.NoError(t, result.Err)
+		rows++
+		assert.Equal(t, rows, result.Document.Metadata["row"])
+	}
+	assert.Equal(t, 20, rows)
+}
+
+func TestLazyLoadCanceled(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/test.csv")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	results := NewCSV(file).LazyLoad(ctx)
+	<-results
+	cancel()
+	for range results { //nolint:revive // drain until the loader stops.
+	}
+
+	_, err = NewCSV(strings.NewReader("a\n1\n")).Load(ctx)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestLoadIntoVectorStore(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/test.csv")
+	require.NoError(t, err)
+
+	store := &batchStore{}
+	ids, err := LoadIntoVectorStore(context.Background(), NewCSV(file), store, WithBatchSize(8))
+	require.NoError(t, err)
+	assert.Len(t, ids, 20)
+
+	sizes := make([]int, 0, len(store.batches))
+	for _, batch := range store.batches {
+		sizes = append(sizes, len(batch))
+	}
+	assert.Equal(t, []int{8, 8, 4}, sizes)
+}
+
+func TestLoadIntoVectorStoreSplit(t *testing.T) {
+	t.Parallel()
+
+	splitter := textsplitter.NewRecursiveCharacter(
+		textsplitter.WithChunkSize(4),
+		textsplitter.WithChunkOverlap(0),
+		textsplitter.WithSeparators([]string{" "}),
+	)
+	store := &batchStore{}
+	ids, err := LoadIntoVectorStore(context.Background(), NewText(strings.NewReader("Foo Bar Baz")), store,
+		WithSplitter(splitter), WithBatchSize(2))
+	require.NoError(t, err)
+	assert.Len(t, ids, 3)
+	require.Len(t, store.batches, 2)
+	assert.Equal(t, "Foo", store.batches[0][0].PageContent)
+	assert.Equal(t, "Baz", store.batches[1][0].PageContent)
+}
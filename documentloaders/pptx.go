@@ -0,0 +1,215 @@
+package documentloaders
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// PPTX loads the slides of a PowerPoint presentation from an io.ReaderAt.
+type PPTX struct {
+	r io.ReaderAt
+	s int64
+}
+
+var _ LazyLoader = PPTX{}
+
+// NewPPTX creates a new PowerPoint presentation loader with an io.ReaderAt and
+// the size of the presentation.
+func NewPPTX(r io.ReaderAt, size int64) PPTX {
+	return PPTX{r: r, s: size}
+}
+
+// Load reads the presentation and returns a document per slide, with the text
+// of the slide followed by its speaker notes. The number of the slide is set
+// as "slide" in the metadata, the number of slides as "total_slides" and the
+// title of the slide, if any, as "title".
+func (p PPTX) Load(ctx context.Context) ([]schema.Document, error) {
+	return collectDocuments(ctx, p.LazyLoad(ctx))
+}
+
+// LazyLoad reads the presentation and sends the documents returned by Load, one
+// slide at a time.
+func (p PPTX) LazyLoad(ctx context.Context) <-chan DocumentResult {
+	return lazyLoad(ctx, func(yield func(schema.Document) bool) error {
+		archive, err := openZipArchive(p.r, p.s)
+		if err != nil {
+			return err
+		}
+		slides, err := pptxSlides(archive)
+		if err != nil {
+			return err
+		}
+
+		for i, slide := range slides {
+			doc, err := pptxSlide(archive, slide)
+			if err != nil {
+				return err
+			}
+			doc.Metadata["slide"] = i + 1
+			doc.Metadata["total_slides"] = len(slides)
+			if !yield(doc) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// LoadAndSplit reads the presentation and splits the documents using a text
+// splitter.
+func (p PPTX) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := p.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+// pptxSlides returns the parts of the slides of a presentation, in order.
+func pptxSlides(archive *zipArchive) ([]string, error) {
+	const presentationPart = "ppt/presentation.xml"
+
+	var presentation struct {
+		Slides []struct {
+			Attr []xml.Attr `xml:",any,attr"`
+		} `xml:"sldIdLst>sldId"`
+	}
+	if err := archive.unmarshal(presentationPart, &presentation); err != nil {
+		return nil, err
+	}
+	targets, _, err := archive.relationships(presentationPart)
+	if err != nil {
+		return nil, err
+	}
+
+	slides := make([]string, 0, len(presentation.Slides))
+	for _, slide := range presentation.Slides {
+		for _, a := range slide.Attr {
+			if a.Name.Local == "id" && a.Name.Space != "" {
+				slides = append(slides, targets[a.Value])
+			}
+		}
+	}
+	return slides, nil
+}
+
+// pptxSlide returns the document of a slide.
+func pptxSlide(archive *zipArchive, part string) (schema.Document, error) {
+	shapes, err := pptxShapes(archive, part)
+	if err != nil {
+		return schema.Document{}, err
+	}
+
+	metadata := map[string]any{}
+	texts := make([]string, 0, len(shapes))
+	for _, shape := range shapes {
+		if _, ok := metadata["title"]; !ok && (shape.placeholder == "title" || shape.placeholder == "ctrTitle") {
+			metadata["title"] = strings.ReplaceAll(shape.text, "\n", " ")
+		}
+		texts = append(texts, shape.text)
+	}
+
+	// Add the body of the notes of the slide, if any.
+	targets, byType, err := archive.relationships(part)
+	if err != nil && !errors.Is(err, ErrMissingPart) {
+		return schema.Document{}, err
+	}
+	if id, ok := byType["notesSlide"]; ok {
+		notes, err := pptxShapes(archive, targets[id])
+		if err != nil {
+			return schema.Document{}, err
+		}
+		var noteTexts []string
+		for _, shape := range notes {
+			if shape.placeholder == "body" {
+				noteTexts = append(noteTexts, shape.text)
+			}
+		}
+		if len(noteTexts) > 0 {
+			texts = append(texts, "Notes:\n"+strings.Join(noteTexts, "\n"))
+		}
+	}
+
+	return schema.Document{
+		PageContent: strings.Join(texts, "\n\n"),
+		Metadata:    metadata,
+	}, nil
+}
+
+// pptxShape is the text of a shape of a slide, with its placeholder type.
+type pptxShape struct {
+	placeholder string
+	text        string
+}
+
+// pptxShapes returns the shapes and graphic frames, such as tables, of a slide
+// that contain text.
+func pptxShapes(archive *zipArchive, part string) ([]pptxShape, error) { //nolint:cyclop
+	decoder, closeFn, err := archive.decoder(part)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+
+	var (
+		shapes     []pptxShape
+		shape      *pptxShape
+		paragraphs []string
+		paragraph  strings.Builder
+		inText     bool
+	)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return shapes, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "sp", "graphicFrame":
+				shape, paragraphs = &pptxShape{}, nil
+			case "ph":
+				if shape != nil {
+					shape.placeholder = attr(t, "type")
+					if shape.placeholder == "" {
+						shape.placeholder = "obj"
+					}
+				}
+			case "p":
+				paragraph.Reset()
+			case "t":
+				inText = true
+			case "br":
+				paragraph.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				paragraph.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if text := strings.TrimSpace(paragraph.String()); text != "" {
+					paragraphs = append(paragraphs, text)
+				}
+			case "sp", "graphicFrame":
+				if shape != nil && len(paragraphs) > 0 {
+					shape.text = strings.Join(paragraphs, "\n")
+					shapes = append(shapes, *shape)
+				}
+				shape = nil
+			}
+		}
+	}
+}
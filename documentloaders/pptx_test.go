@@ -0,0 +1,28 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPPTXLoader(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/sample.pptx")
+	require.NoError(t, err)
+	info, err := file.Stat()
+	require.NoError(t, err)
+
+	docs, err := NewPPTX(file, info.Size()).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "Welcome\n\nTeam offsite 2024", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"slide": 1, "total_slides": 2, "title": "Welcome"}, docs[0].Metadata)
+
+	assert.Equal(t, "Roadmap\n\nShip v2\nHire two engineers\n\nNotes:\nMention the budget.", docs[1].PageContent)
+	assert.Equal(t, map[string]any{"slide": 2, "total_slides": 2, "title": "Roadmap"}, docs[1].Metadata)
+}
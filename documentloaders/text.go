@@ -14,7 +14,7 @@ type Text struct {
 	r io.Reader
 }
 
-var _ Loader = Text{}
+var _ LazyLoader = Text{}
 
 // NewText creates a new text loader with an io.Reader.
 func NewText(r io.Reader) Text {
@@ -24,19 +24,25 @@ func NewText(r io.Reader) Text {
 }
 
 // Load reads from the io.Reader and returns a single document with the data.
-func (l Text) Load(_ context.Context) ([]schema.Document, error) {
-	buf := new(bytes.Buffer)
-	_, err := io.Copy(buf, l.r)
-	if err != nil {
-		return nil, err
-	}
+func (l Text) Load(ctx context.Context) ([]schema.Document, error) {
+	return collectDocuments(ctx, l.LazyLoad(ctx))
+}
+
+// LazyLoad reads from the io.Reader and sends a single document with the data.
+func (l Text) LazyLoad(ctx context.Context) <-chan DocumentResult {
+	return lazyLoad(ctx, func(yield func(schema.Document) bool) error {
+		buf := new(bytes.Buffer)
+		_, err := io.Copy(buf, l.r)
+		if err != nil {
+			return err
+		}
 
-	return []schema.Document{
-		{
+		yield(schema.Document{
 			PageContent: buf.String(),
 			Metadata:    map[string]any{},
-		},
-	}, nil
+		})
+		return nil
+	})
 }
 
 // LoadAndSplit reads text data from the io.Reader and splits it into multiple
@@ -0,0 +1,329 @@
+package documentloaders
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+	"golang.org/x/exp/slices"
+)
+
+// XLSX loads the sheets of an Excel workbook from an io.ReaderAt.
+type XLSX struct {
+	r       io.ReaderAt
+	s       int64
+	rows    bool
+	columns []string
+	sheets  []string
+}
+
+var _ LazyLoader = XLSX{}
+
+// _xlsxMaxColumns is the number of columns of a sheet, the last one being XFD.
+const _xlsxMaxColumns = 16384
+
+// XLSXOptions are options for the XLSX loader.
+type XLSXOptions func(x *XLSX)
+
+// WithRowDocuments makes the XLSX loader return a document per row instead of
+// a document per sheet. As with the CSV loader, the first row of each sheet is
+// used as header, and the optional columns filter the values of the rows.
+func WithRowDocuments(columns ...string) XLSXOptions {
+	return func(x *XLSX) {
+		x.rows = true
+		x.columns = columns
+	}
+}
+
+// WithSheets sets the names of the sheets to load. By default, all the sheets
+// are loaded.
+func WithSheets(sheets ...string) XLSXOptions {
+	return func(x *XLSX) {
+		x.sheets = sheets
+	}
+}
+
+// NewXLSX creates a new Excel workbook loader with an io.ReaderAt and the size
+// of the workbook.
+func NewXLSX(r io.ReaderAt, size int64, opts ...XLSXOptions) XLSX {
+	x := XLSX{r: r, s: size}
+	for _, opt := range opts {
+		opt(&x)
+	}
+	return x
+}
+
+// Load reads the workbook and returns a document per sheet, with the sheet
+// formatted as a markdown table, or a document per row if WithRowDocuments is
+// used. The name of the sheet is set as "sheet" in the metadata, and the
+// number of the row, not counting the header, as "row".
+func (x XLSX) Load(ctx context.Context) ([]schema.Document, error) {
+	return collectDocuments(ctx, x.LazyLoad(ctx))
+}
+
+// LazyLoad reads the workbook and sends the documents returned by Load, one
+// sheet or row at a time.
+func (x XLSX) LazyLoad(ctx context.Context) <-chan DocumentResult {
+	return lazyLoad(ctx, func(yield func(schema.Document) bool) error {
+		archive, err := openZipArchive(x.r, x.s)
+		if err != nil {
+			return err
+		}
+		sheets, err := xlsxSheets(archive)
+		if err != nil {
+			return err
+		}
+		sharedStrings, err := xlsxSharedStrings(archive)
+		if err != nil {
+			return err
+		}
+
+		for _, sheet := range sheets {
+			if len(x.sheets) > 0 && !slices.Contains(x.sheets, sheet.name) {
+				continue
+			}
+			rows, err := xlsxRows(archive, sheet.part, sharedStrings)
+			if err != nil {
+				return err
+			}
+			if !x.yieldSheet(sheet.name, rows, yield) {
+				return nil
+			}
+		}
+		return nil
+	})
+}
+
+// LoadAndSplit reads the workbook and splits the documents using a text
+// splitter.
+func (x XLSX) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := x.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func (x XLSX) yieldSheet(name string, rows [][]string, yield func(schema.Document) bool) bool {
+	if len(rows) == 0 {
+		return true
+	}
+	if !x.rows {
+		return yield(schema.Document{
+			PageContent: markdownTable(rows),
+			Metadata:    map[string]any{"sheet": name},
+		})
+	}
+
+	header := rows[0]
+	for rown, row := range rows[1:] {
+		var content []string
+		for i, value := range row {
+			column := fmt.Sprintf("column %d", i+1)
+			if i < len(header) && header[i] != "" {
+				column = header[i]
+			}
+			if value == "" || len(x.columns) > 0 && !slices.Contains(x.columns, column) {
+				continue
+			}
+			content = append(content, fmt.Sprintf("%s: %s", column, value))
+		}
+		if !yield(schema.Document{
+			PageContent: strings.Join(content, "\n"),
+			Metadata:    map[string]any{"sheet": name, "row": rown + 1},
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+type xlsxSheet struct {
+	name string
+	part string
+}
+
+// xlsxSheets returns the sheets of a workbook, in order.
+func xlsxSheets(archive *zipArchive) ([]xlsxSheet, error) {
+	const workbookPart = "xl/workbook.xml"
+
+	var workbook struct {
+		Sheets []struct {
+			Name string     `xml:"name,attr"`
+			Attr []xml.Attr `xml:",any,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := archive.unmarshal(workbookPart, &workbook); err != nil {
+		return nil, err
+	}
+	targets, _, err := archive.relationships(workbookPart)
+	if err != nil {
+		return nil, err
+	}
+
+	sheets := make([]xlsxSheet, 0, len(workbook.Sheets))
+	for _, sheet := range workbook.Sheets {
+		for _, a := range sheet.Attr {
+			if a.Name.Local == "id" {
+				sheets = append(sheets, xlsxSheet{name: sheet.Name, part: targets[a.Value]})
+			}
+		}
+	}
+	return sheets, nil
+}
+
+// xlsxSharedStrings returns the shared strings table of a workbook.
+func xlsxSharedStrings(archive *zipArchive) ([]string, error) {
+	const sharedStringsPart = "xl/sharedStrings.xml"
+	if !archive.has(sharedStringsPart) {
+		return nil, nil
+	}
+
+	decoder, closeFn, err := archive.decoder(sharedStringsPart)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+
+	var sharedStrings []string
+	var current strings.Builder
+	inText, inPhonetic := false, false
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return sharedStrings, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				current.Reset()
+			case "t":
+				inText = true
+			case "rPh":
+				inPhonetic = true
+			}
+		case xml.CharData:
+			if inText && !inPhonetic {
+				current.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				sharedStrings = append(sharedStrings, current.String())
+			case "t":
+				inText = false
+			case "rPh":
+				inPhonetic = false
+			}
+		}
+	}
+}
+
+// xlsxRows returns the values of the rows of a sheet, placing each value in the
+// column given by its cell reference.
+func xlsxRows(archive *zipArchive, part string, sharedStrings []string) ([][]string, error) { //nolint:cyclop
+	decoder, closeFn, err := archive.decoder(part)
+	if err != nil {
+		return nil, err
+	}
+	defer closeFn()
+
+	var (
+		rows     [][]string
+		row      []string
+		column   int
+		cellType string
+		value    strings.Builder
+		inValue  bool
+	)
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				row = nil
+			case "c":
+				column = xlsxColumn(attr(t, "r"), len(row))
+				cellType = attr(t, "t")
+				value.Reset()
+			case "v", "t":
+				inValue = true
+			}
+		case xml.CharData:
+			if inValue {
+				value.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "v", "t":
+				inValue = false
+			case "c":
+				text := xlsxCellValue(value.String(), cellType, sharedStrings)
+				for len(row) <= column {
+					row = append(row, "")
+				}
+				row[column] = text
+			case "row":
+				if slices.ContainsFunc(row, func(v string) bool { return v != "" }) {
+					rows = append(rows, row)
+				}
+			}
+		}
+	}
+	return rows, nil
+}
+
+// xlsxCellValue returns the text of a cell from its raw value and type.
+func xlsxCellValue(raw, cellType string, sharedStrings []string) string {
+	switch cellType {
+	case "s":
+		i, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || i < 0 || i >= len(sharedStrings) {
+			return ""
+		}
+		return sharedStrings[i]
+	case "b":
+		if strings.TrimSpace(raw) == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	default:
+		return strings.TrimSpace(raw)
+	}
+}
+
+// xlsxColumn returns the zero based column of a cell reference such as "B3",
+// or next if the reference is missing or past the last column of a sheet.
+func xlsxColumn(ref string, next int) int {
+	column := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		column = column*26 + int(r-'A') + 1
+		if column > _xlsxMaxColumns {
+			return next
+		}
+	}
+	if column == 0 {
+		return next
+	}
+	return column - 1
+}
@@ -0,0 +1,66 @@
+package documentloaders
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXLSXLoader(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/sample.xlsx")
+	require.NoError(t, err)
+	info, err := file.Stat()
+	require.NoError(t, err)
+
+	docs, err := NewXLSX(file, info.Size()).Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	expected := "| name | age | active |\n" +
+		"| --- | --- | --- |\n" +
+		"| John Doe | 25 | TRUE |\n" +
+		"| Jane Smith |  | FALSE |"
+	assert.Equal(t, expected, docs[0].PageContent)
+	assert.Equal(t, map[string]any{"sheet": "People"}, docs[0].Metadata)
+	assert.Equal(t, map[string]any{"sheet": "Cities"}, docs[1].Metadata)
+}
+
+func TestXLSXLoaderRows(t *testing.T) {
+	t.Parallel()
+	file, err := os.Open("./testdata/sample.xlsx")
+	require.NoError(t, err)
+	info, err := file.Stat()
+	require.NoError(t, err)
+
+	docs, err := NewXLSX(file, info.Size(), WithRowDocuments("name", "active"), WithSheets("People")).
+		Load(context.Background())
+	require.NoError(t, err)
+	require.Len(t, docs, 2)
+
+	assert.Equal(t, "name: John Doe\nactive: TRUE", docs[0].PageContent)
+	assert.Equal(t, map[string]any{"sheet": "People", "row": 1}, docs[0].Metadata)
+	assert.Equal(t, "name: Jane Smith\nactive: FALSE", docs[1].PageContent)
+	assert.Equal(t, map[string]any{"sheet": "People", "row": 2}, docs[1].Metadata)
+}
+
+func TestXLSXColumn(t *testing.T) {
+	t.Parallel()
+	tests := map[string]int{
+		"A1":              0,
+		"B3":              1,
+		"AA10":            26,
+		"XFD1":            16383,
+		"":                5,
+		"12":              5,
+		"XFE1":            5,
+		"ZZZZZZ1":         5,
+		"ZZZZZZZZZZZZZZ1": 5,
+	}
+	for ref, expected := range tests {
+		assert.Equal(t, expected, xlsxColumn(ref, 5), ref)
+	}
+}
@@ -0,0 +1,202 @@
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ErrUnsupportedType is returned when a JSON schema cannot be generated for a Go type.
+var ErrUnsupportedType = errors.New("unsupported type")
+
+// GenerateSchemaForType returns the JSON schema of the type of v, following the
+// rules of encoding/json for field names, embedded structs and byte slices,
+// which are base64 strings. Fields tagged with `json:",omitempty"` or of
+// pointer type are optional, as are the fields of embedded struct pointers, and
+// all other fields are required. The "describe" tag sets the description of a
+// field, and the "enum" tag, a comma separated list, restricts the values of a
+// string field. Struct objects do not allow additional properties.
+func GenerateSchemaForType(v any) (*Definition, error) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return nil, fmt.Errorf("%w: nil", ErrUnsupportedType)
+	}
+	return reflectType(t, map[reflect.Type]bool{})
+}
+
+func reflectType(t reflect.Type, seen map[reflect.Type]bool) (*Definition, error) { //nolint:cyclop
+	if t == reflect.TypeOf(time.Time{}) {
+		return &Definition{Type: String, Description: "RFC 3339 date-time"}, nil
+	}
+
+	switch t.Kind() { //nolint:exhaustive
+	case reflect.Pointer:
+		return reflectType(t.Elem(), seen)
+	case reflect.String:
+		return &Definition{Type: String}, nil
+	case reflect.Bool:
+		return &Definition{Type: Boolean}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Definition{Type: Integer}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Definition{Type: Number}, nil
+	case reflect.Slice, reflect.Array:
+		// encoding/json encodes byte slices as base64 strings.
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return &Definition{Type: String, Description: "base64-encoded bytes"}, nil
+		}
+		items, err := reflectType(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		return &Definition{Type: Array, Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("%w: map key %s", ErrUnsupportedType, t.Key())
+		}
+		return &Definition{Type: Object}, nil
+	case reflect.Struct:
+		return reflectStruct(t, seen)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, t)
+	}
+}
+
+func reflectStruct(t reflect.Type, seen map[reflect.Type]bool) (*Definition, error) {
+	if seen[t] {
+		return nil, fmt.Errorf("%w: recursive type %s", ErrUnsupportedType, t)
+	}
+	seen[t] = true
+	defer delete(seen, t)
+
+	def := &Definition{
+		Type:                 Object,
+		Properties:           map[string]Definition{},
+		Required:             []string{},
+		AdditionalProperties: false,
+	}
+	for _, field := range dominantFields(structFields(t, 0, false, map[reflect.Type]bool{})) {
+		prop, err := reflectType(field.Type, seen)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %w", field.Name, err)
+		}
+		if describe := field.Tag.Get("describe"); describe != "" {
+			prop.Description = describe
+		}
+		if enum := field.Tag.Get("enum"); enum != "" {
+			prop.Enum = strings.Split(enum, ",")
+		}
+
+		def.Properties[field.name] = *prop
+		if !field.optional && field.Type.Kind() != reflect.Pointer {
+			def.Required = append(def.Required, field.name)
+		}
+	}
+	return def, nil
+}
+
+// structField is a field of a struct encoded by encoding/json, possibly
+// promoted from an embedded struct.
+type structField struct {
+	reflect.StructField
+
+	name     string
+	depth    int
+	tagged   bool
+	optional bool
+}
+
+// structFields returns the fields of t encoded by encoding/json, with the
+// fields of the embedded structs without a JSON name flattened, as done by
+// encoding/json. The fields of embedded pointers to structs are optional.
+func structFields(t reflect.Type, depth int, optional bool, embedded map[reflect.Type]bool) []structField {
+	if embedded[t] {
+		return nil
+	}
+	embedded[t] = true
+	defer delete(embedded, t)
+
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitEmpty, skip := parseJSONTag(field)
+		if skip {
+			continue
+		}
+		if field.Anonymous {
+			ft := field.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			// Unexported embedded structs are flattened, but not pointers to
+			// them, nor unexported embedded types of other kinds.
+			if !field.IsExported() && (ft.Kind() != reflect.Struct || field.Type.Kind() == reflect.Pointer) {
+				continue
+			}
+			if field.Tag.Get("json") == "" || strings.HasPrefix(field.Tag.Get("json"), ",") {
+				if ft.Kind() == reflect.Struct && ft != reflect.TypeOf(time.Time{}) {
+					fields = append(fields, structFields(ft, depth+1,
+						optional || field.Type.Kind() == reflect.Pointer, embedded)...)
+					continue
+				}
+			}
+		} else if !field.IsExported() {
+			continue
+		}
+
+		fields = append(fields, structField{
+			StructField: field,
+			name:        name,
+			depth:       depth,
+			tagged:      field.Tag.Get("json") != "" && !strings.HasPrefix(field.Tag.Get("json"), ","),
+			optional:    optional || omitEmpty,
+		})
+	}
+	return fields
+}
+
+// dominantFields resolves the fields with the same name as encoding/json does:
+// the shallowest field wins, then the field with a JSON name, and fields that
+// are still ambiguous are dropped.
+func dominantFields(fields []structField) []structField {
+	byName := map[string][]int{}
+	for i, field := range fields {
+		byName[field.name] = append(byName[field.name], i)
+	}
+
+	dominant := make([]structField, 0, len(byName))
+	for i, field := range fields {
+		var shallowest, tagged []int
+		for _, j := range byName[field.name] {
+			switch other := fields[j]; {
+			case len(shallowest) > 0 && other.depth > fields[shallowest[0]].depth:
+				continue
+			case len(shallowest) > 0 && other.depth < fields[shallowest[0]].depth:
+				shallowest, tagged = nil, nil
+			}
+			shallowest = append(shallowest, j)
+			if fields[j].tagged {
+				tagged = append(tagged, j)
+			}
+		}
+		if (len(shallowest) == 1 && shallowest[0] == i) || (len(shallowest) > 1 && len(tagged) == 1 && tagged[0] == i) {
+			dominant = append(dominant, field)
+		}
+	}
+	return dominant
+}
+
+func parseJSONTag(field reflect.StructField) (string, bool, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(","+opts+",", ",omitempty,"), false
+}
@@ -0,0 +1,144 @@
+package jsonschema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/jsonschema"
+)
+
+type address struct {
+	City string `json:"city"`
+}
+
+type person struct {
+	Name     string            `json:"name"               describe:"full name"`
+	Age      int               `json:"age"`
+	Role     string            `json:"role"               enum:"admin,user"`
+	Email    *string           `json:"email"`
+	Tags     []string          `json:"tags,omitempty"`
+	Address  address           `json:"address"`
+	Labels   map[string]string `json:"labels,omitempty"`
+	Internal string            `json:"-"`
+}
+
+func TestGenerateSchemaForType(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.GenerateSchemaForType(person{})
+	require.NoError(t, err)
+
+	got, err := json.Marshal(def)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "description": "full name", "properties": {}},
+			"age": {"type": "integer", "properties": {}},
+			"role": {"type": "string", "enum": ["admin", "user"], "properties": {}},
+			"email": {"type": "string", "properties": {}},
+			"tags": {"type": "array", "items": {"type": "string", "properties": {}}, "properties": {}},
+			"address": {
+				"type": "object",
+				"properties": {"city": {"type": "string", "properties": {}}},
+				"required": ["city"],
+				"additionalProperties": false
+			},
+			"labels": {"type": "object", "properties": {}}
+		},
+		"required": ["name", "age", "role", "address"],
+		"additionalProperties": false
+	}`, string(got))
+}
+
+type timestamps struct {
+	Created string `json:"created"`
+	Updated string `json:"updated"`
+}
+
+type Audit struct {
+	By      string `json:"by"`
+	Updated int    `json:"updated"`
+	Title   int    `json:"title"`
+}
+
+type Extra struct {
+	Note string `json:"note"`
+}
+
+type document struct {
+	timestamps
+	*Audit
+	Extra `json:"extra"`
+
+	Title string `json:"title"`
+	Data  []byte `json:"data"`
+}
+
+func TestGenerateSchemaForTypeEmbedded(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.GenerateSchemaForType(document{})
+	require.NoError(t, err)
+
+	got, err := json.Marshal(def)
+	require.NoError(t, err)
+	// As with encoding/json, title is not promoted as it is shadowed by a
+	// shallower field, updated is dropped as it is ambiguous, and the fields
+	// of the embedded pointer are optional.
+	assert.JSONEq(t, `{
+		"type": "object",
+		"properties": {
+			"created": {"type": "string", "properties": {}},
+			"by": {"type": "string", "properties": {}},
+			"extra": {
+				"type": "object",
+				"properties": {"note": {"type": "string", "properties": {}}},
+				"required": ["note"],
+				"additionalProperties": false
+			},
+			"title": {"type": "string", "properties": {}},
+			"data": {"type": "string", "description": "base64-encoded bytes", "properties": {}}
+		},
+		"required": ["created", "extra", "title", "data"],
+		"additionalProperties": false
+	}`, string(got))
+
+	data, err := json.Marshal(document{Audit: &Audit{By: "ann"}, Data: []byte("hi")})
+	require.NoError(t, err)
+	var value any
+	require.NoError(t, json.Unmarshal(data, &value))
+	require.NoError(t, def.Validate(value))
+}
+
+func TestDefinitionValidate(t *testing.T) {
+	t.Parallel()
+
+	def, err := jsonschema.GenerateSchemaForType(person{})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		data  string
+		valid bool
+	}{
+		{`{"name":"Ann","age":30,"role":"admin","address":{"city":"Paris"}}`, true},
+		{`{"name":"Ann","age":30,"role":"admin","email":null,"address":{"city":"Paris"}}`, true},
+		{`{"name":"Ann","age":30.5,"role":"admin","address":{"city":"Paris"}}`, false},
+		{`{"name":"Ann","age":30,"role":"owner","address":{"city":"Paris"}}`, false},
+		{`{"name":"Ann","age":30,"role":"admin"}`, false},
+		{`{"name":"Ann","age":30,"role":"admin","address":{"city":"Paris"},"extra":1}`, false},
+		{`{"name":"Ann","age":30,"role":"admin","address":{"city":"Paris"},"tags":[1]}`, false},
+	}
+	for _, tc := range testCases {
+		var data any
+		require.NoError(t, json.Unmarshal([]byte(tc.data), &data))
+		err := def.Validate(data)
+		if tc.valid {
+			assert.NoError(t, err, tc.data)
+		} else {
+			assert.ErrorIs(t, err, jsonschema.ErrValidation, tc.data)
+		}
+	}
+}
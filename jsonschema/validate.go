@@ -0,0 +1,130 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+)
+
+// ErrValidation is returned when a value does not match a JSON schema.
+var ErrValidation = errors.New("value does not match schema")
+
+// Validate checks that data, a value decoded by encoding/json, matches the
+// schema. Types, required properties, additional properties, enums and array
+// items are checked; other keywords are ignored.
+func (d Definition) Validate(data any) error {
+	return d.validate(data, "$")
+}
+
+func (d Definition) validate(data any, path string) error { //nolint:cyclop
+	if d.Type == "" {
+		return nil
+	}
+	if data == nil {
+		if d.Type == Null {
+			return nil
+		}
+		return fmt.Errorf("%w: %s: expected %s, got null", ErrValidation, path, d.Type)
+	}
+
+	switch d.Type {
+	case Object:
+		object, ok := data.(map[string]any)
+		if !ok {
+			return typeError(path, d.Type, data)
+		}
+		return d.validateObject(object, path)
+	case Array:
+		array, ok := data.([]any)
+		if !ok {
+			return typeError(path, d.Type, data)
+		}
+		if d.Items == nil {
+			return nil
+		}
+		for i, item := range array {
+			if err := d.Items.validate(item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case String:
+		s, ok := data.(string)
+		if !ok {
+			return typeError(path, d.Type, data)
+		}
+		if len(d.Enum) > 0 && !slices.Contains(d.Enum, s) {
+			return fmt.Errorf("%w: %s: %q is not one of %q", ErrValidation, path, s, d.Enum)
+		}
+		return nil
+	case Number, Integer:
+		n, ok := toFloat(data)
+		if !ok {
+			return typeError(path, d.Type, data)
+		}
+		if d.Type == Integer && n != math.Trunc(n) {
+			return fmt.Errorf("%w: %s: expected integer, got %v", ErrValidation, path, n)
+		}
+		return nil
+	case Boolean:
+		if _, ok := data.(bool); !ok {
+			return typeError(path, d.Type, data)
+		}
+		return nil
+	case Null:
+		return typeError(path, d.Type, data)
+	default:
+		return nil
+	}
+}
+
+func (d Definition) validateObject(object map[string]any, path string) error {
+	for _, name := range d.Required {
+		if _, ok := object[name]; !ok {
+			return fmt.Errorf("%w: %s: missing required property %q", ErrValidation, path, name)
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		prop, ok := d.Properties[name]
+		if !ok {
+			if additional, isBool := d.AdditionalProperties.(bool); isBool && !additional {
+				return fmt.Errorf("%w: %s: unexpected property %q", ErrValidation, path, name)
+			}
+			continue
+		}
+		if object[name] == nil && !slices.Contains(d.Required, name) {
+			// Optional properties may be null, which encoding/json treats as absent.
+			continue
+		}
+		if err := prop.validate(object[name], path+"."+name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toFloat(data any) (float64, bool) {
+	switch n := data.(type) {
+	case float64:
+		return n, true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func typeError(path string, expected DataType, data any) error {
+	return fmt.Errorf("%w: %s: expected %s, got %T", ErrValidation, path, expected, data)
+}
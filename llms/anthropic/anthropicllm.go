@@ -75,6 +75,7 @@ func (o *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOptio
 
 // GenerateContent implements the Model interface.
 func (o *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunTypeLLM, "anthropic")
 	if o.CallbacksHandler != nil {
 		o.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
@@ -167,6 +168,7 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
 					GenerationInfo: map[string]any{
 						"InputTokens":  result.Usage.InputTokens,
 						"OutputTokens": result.Usage.OutputTokens,
+						"Model":        result.Model,
 					},
 				}
 			} else {
@@ -192,6 +194,7 @@ func generateMessagesContent(ctx context.Context, o *LLM, messages []llms.Messag
 					GenerationInfo: map[string]any{
 						"InputTokens":  result.Usage.InputTokens,
 						"OutputTokens": result.Usage.OutputTokens,
+						"Model":        result.Model,
 					},
 				}
 			} else {
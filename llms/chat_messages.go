@@ -170,6 +170,17 @@ func getMessageRole(m ChatMessage, humanPrefix, aiPrefix string) (string, error)
 type ChatMessageModelData struct {
 	Content string `bson:"content" json:"content"`
 	Type    string `bson:"type"    json:"type"`
+
+	// Role is the role of a generic message.
+	Role string `bson:"role,omitempty" json:"role,omitempty"`
+	// Name is the name of a generic or function message.
+	Name string `bson:"name,omitempty" json:"name,omitempty"`
+	// ToolCallID is the ID of the tool call a tool message responds to.
+	ToolCallID string `bson:"tool_call_id,omitempty" json:"tool_call_id,omitempty"`
+	// FunctionCall is the function call of an AI message.
+	FunctionCall *FunctionCall `bson:"function_call,omitempty" json:"function_call,omitempty"`
+	// ToolCalls are the tool calls of an AI message.
+	ToolCalls []ToolCall `bson:"tool_calls,omitempty" json:"tool_calls,omitempty"`
 }
 
 type ChatMessageModel struct {
@@ -180,9 +191,21 @@ type ChatMessageModel struct {
 func (c ChatMessageModel) ToChatMessage() ChatMessage {
 	switch c.Type {
 	case string(ChatMessageTypeAI):
-		return AIChatMessage{Content: c.Data.Content}
+		return AIChatMessage{
+			Content:      c.Data.Content,
+			FunctionCall: c.Data.FunctionCall,
+			ToolCalls:    c.Data.ToolCalls,
+		}
 	case string(ChatMessageTypeHuman):
 		return HumanChatMessage{Content: c.Data.Content}
+	case string(ChatMessageTypeSystem):
+		return SystemChatMessage{Content: c.Data.Content}
+	case string(ChatMessageTypeGeneric):
+		return GenericChatMessage{Content: c.Data.Content, Role: c.Data.Role, Name: c.Data.Name}
+	case string(ChatMessageTypeFunction):
+		return FunctionChatMessage{Content: c.Data.Content, Name: c.Data.Name}
+	case string(ChatMessageTypeTool):
+		return ToolChatMessage{Content: c.Data.Content, ID: c.Data.ToolCallID}
 	default:
 		slog.Warn("convert to chat message failed with invalid message type", "type", c.Type)
 		return nil
@@ -191,11 +214,25 @@ func (c ChatMessageModel) ToChatMessage() ChatMessage {
 
 // ConvertChatMessageToModel Convert a ChatMessage to a ChatMessageModel.
 func ConvertChatMessageToModel(m ChatMessage) ChatMessageModel {
+	data := ChatMessageModelData{
+		Type:    string(m.GetType()),
+		Content: m.GetContent(),
+	}
+	switch msg := m.(type) {
+	case AIChatMessage:
+		data.FunctionCall = msg.FunctionCall
+		data.ToolCalls = msg.ToolCalls
+	case GenericChatMessage:
+		data.Role = msg.Role
+		data.Name = msg.Name
+	case FunctionChatMessage:
+		data.Name = msg.Name
+	case ToolChatMessage:
+		data.ToolCallID = msg.ID
+	}
+
 	return ChatMessageModel{
 		Type: string(m.GetType()),
-		Data: ChatMessageModelData{
-			Type:    string(m.GetType()),
-			Content: m.GetContent(),
-		},
+		Data: data,
 	}
 }
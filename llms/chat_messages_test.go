@@ -1,6 +1,7 @@
 package llms_test
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/tmc/langchaingo/llms"
@@ -68,3 +69,26 @@ type unsupportedChatMessage struct{}
 
 func (m unsupportedChatMessage) GetType() llms.ChatMessageType { return "unsupported" }
 func (m unsupportedChatMessage) GetContent() string            { return "Unsupported message" }
+
+func TestChatMessageModelRoundTrip(t *testing.T) {
+	t.Parallel()
+	messages := []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "Be brief."},
+		llms.HumanChatMessage{Content: "What's the weather?"},
+		llms.AIChatMessage{ToolCalls: []llms.ToolCall{{
+			ID:           "call_1",
+			Type:         "function",
+			FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`},
+		}}},
+		llms.ToolChatMessage{ID: "call_1", Content: "Sunny"},
+		llms.FunctionChatMessage{Name: "weather", Content: "Sunny"},
+		llms.GenericChatMessage{Role: "Moderator", Name: "mod", Content: "Stay on topic."},
+	}
+
+	for _, m := range messages {
+		got := llms.ConvertChatMessageToModel(m).ToChatMessage()
+		if !reflect.DeepEqual(m, got) {
+			t.Errorf("expected: %#v, got: %#v", m, got)
+		}
+	}
+}
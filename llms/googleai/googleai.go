@@ -10,6 +10,7 @@ import (
 	"strings"
 
 	"github.com/google/generative-ai-go/genai"
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/internal/imageutil"
 	"github.com/tmc/langchaingo/llms"
 	"google.golang.org/api/iterator"
@@ -42,6 +43,7 @@ func (g *GoogleAI) GenerateContent(
 	messages []llms.MessageContent,
 	options ...llms.CallOption,
 ) (*llms.ContentResponse, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunTypeLLM, "googleai")
 	if g.CallbacksHandler != nil {
 		g.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
@@ -113,6 +115,10 @@ func (g *GoogleAI) GenerateContent(
 		return nil, err
 	}
 
+	for _, choice := range response.Choices {
+		choice.GenerationInfo["model"] = opts.Model
+	}
+
 	if g.CallbacksHandler != nil {
 		g.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
 	}
@@ -30,6 +30,9 @@ func main() {
 		case *ast.ImportSpec:
 			rewriteImport(x)
 
+		case *ast.BasicLit:
+			rewriteProviderName(x)
+
 		case *ast.FuncDecl:
 			if x.Recv != nil && len(x.Recv.List) == 1 {
 				rewriteReceiverName(x)
@@ -55,6 +58,14 @@ func rewriteImport(x *ast.ImportSpec) {
 	}
 }
 
+// rewriteProviderName renames the "googleai" string literals, such as the name
+// of the runs of the callbacks, to "vertex".
+func rewriteProviderName(x *ast.BasicLit) {
+	if x.Kind == token.STRING && x.Value == `"googleai"` {
+		x.Value = `"vertex"`
+	}
+}
+
 func rewriteReceiverName(fun *ast.FuncDecl) {
 	recv := fun.Recv.List[0]
 	ty := recv.Type.(*ast.StarExpr)
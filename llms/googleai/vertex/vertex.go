@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"cloud.google.com/go/vertexai/genai"
+	"github.com/tmc/langchaingo/callbacks"
 	"github.com/tmc/langchaingo/internal/imageutil"
 	"github.com/tmc/langchaingo/llms"
 	"google.golang.org/api/iterator"
@@ -45,6 +46,7 @@ func (g *Vertex) GenerateContent(
 	messages []llms.MessageContent,
 	options ...llms.CallOption,
 ) (*llms.ContentResponse, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunTypeLLM, "vertex")
 	if g.CallbacksHandler != nil {
 		g.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
@@ -116,6 +118,10 @@ func (g *Vertex) GenerateContent(
 		return nil, err
 	}
 
+	for _, choice := range response.Choices {
+		choice.GenerationInfo["model"] = opts.Model
+	}
+
 	if g.CallbacksHandler != nil {
 		g.CallbacksHandler.HandleLLMGenerateContentEnd(ctx, response)
 	}
@@ -69,6 +69,7 @@ func (m *Model) Call(ctx context.Context, prompt string, options ...llms.CallOpt
 func (m *Model) GenerateContent(ctx context.Context, langchainMessages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) {
 	callOptions := resolveDefaultOptions(sdk.DefaultChatRequestParams, m.clientOptions)
 	setCallOptions(options, callOptions)
+	ctx = callbacks.StartRun(ctx, callbacks.RunTypeLLM, "mistral")
 	m.CallbacksHandler.HandleLLMGenerateContentStart(ctx, langchainMessages)
 
 	chatOpts := mistralChatParamsFromCallOptions(callOptions)
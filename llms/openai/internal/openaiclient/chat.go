@@ -470,6 +470,10 @@ func combineStreamingChatResponse(
 			return nil, streamResponse.Error
 		}
 
+		if streamResponse.Model != "" {
+			response.Model = streamResponse.Model
+		}
+
 		if streamResponse.Usage != nil {
 			response.Usage.CompletionTokens = streamResponse.Usage.CompletionTokens
 			response.Usage.PromptTokens = streamResponse.Usage.PromptTokens
@@ -0,0 +1,6 @@
+// Package structured provides a generic helper that binds a Go type to a model
+// call. A JSON schema is derived from the type, the best mechanism the model
+// supports for structured output is used to request a reply matching the
+// schema, and the reply is validated against the schema and decoded into the
+// type.
+package structured
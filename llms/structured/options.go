@@ -0,0 +1,77 @@
+package structured
+
+import "github.com/tmc/langchaingo/llms"
+
+const (
+	_defaultName        = "respond"
+	_defaultDescription = "Respond to the user with structured data."
+)
+
+// Mode is the mechanism used to ask a model for output matching a schema.
+type Mode string
+
+const (
+	// ModeAuto picks the best mode supported by the model provider.
+	ModeAuto Mode = ""
+	// ModeStrictTool forces the model to call a tool whose parameters are the
+	// schema, with strict schema adherence enabled. Supported by OpenAI.
+	ModeStrictTool Mode = "strict_tool"
+	// ModeTool asks the model to call a tool whose parameters are the schema.
+	ModeTool Mode = "tool"
+	// ModeJSON enables the JSON output mode of the model and describes the
+	// schema in the prompt.
+	ModeJSON Mode = "json"
+	// ModePrompt only describes the schema in the prompt. It works with any
+	// model.
+	ModePrompt Mode = "prompt"
+)
+
+// Options is a set of options for Generate.
+type Options struct {
+	Mode        Mode
+	Name        string
+	Description string
+	CallOptions []llms.CallOption
+}
+
+// Option is a function that configures an Options.
+type Option func(*Options)
+
+// WithMode sets the mode used to request structured output, instead of the
+// mode picked for the model provider.
+func WithMode(mode Mode) Option {
+	return func(o *Options) {
+		o.Mode = mode
+	}
+}
+
+// WithName sets the name of the tool the model is asked to call in the tool
+// modes.
+func WithName(name string) Option {
+	return func(o *Options) {
+		o.Name = name
+	}
+}
+
+// WithDescription sets the description of the requested output, used as the
+// tool description in the tool modes.
+func WithDescription(description string) Option {
+	return func(o *Options) {
+		o.Description = description
+	}
+}
+
+// WithCallOptions sets options passed to the model call.
+func WithCallOptions(options ...llms.CallOption) Option {
+	return func(o *Options) {
+		o.CallOptions = options
+	}
+}
+
+func defaultOptions() Options {
+	return Options{
+		Mode:        ModeAuto,
+		Name:        _defaultName,
+		Description: _defaultDescription,
+	}
+}
@@ -0,0 +1,248 @@
+package structured
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/outputparser"
+)
+
+const _instructions = "Respond only with a JSON value that matches the following JSON schema, without any other text:\n```json\n%s\n```" //nolint:lll
+
+// _wrappedKey is the property holding the value when the requested type is
+// not a JSON object, as tools and most JSON modes require an object.
+const _wrappedKey = "value"
+
+var (
+	// ErrEmptyResponse is returned when the model returns no choices.
+	ErrEmptyResponse = errors.New("empty response from model")
+	// ErrInvalidOutput is returned when the output of the model cannot be
+	// decoded into the requested type.
+	ErrInvalidOutput = errors.New("invalid structured output")
+)
+
+// providerModes maps the package of a model to the best mode it supports.
+// Anthropic and Mistral support tools but ignore the tool choice, so the call
+// of the tool cannot be forced, and they have no JSON mode, so the schema is
+// only given in the prompt.
+//
+//nolint:gochecknoglobals
+var providerModes = map[string]Mode{
+	"github.com/tmc/langchaingo/llms/openai":          ModeStrictTool,
+	"github.com/tmc/langchaingo/llms/anthropic":       ModePrompt,
+	"github.com/tmc/langchaingo/llms/mistral":         ModePrompt,
+	"github.com/tmc/langchaingo/llms/googleai":        ModeJSON,
+	"github.com/tmc/langchaingo/llms/googleai/vertex": ModeJSON,
+	"github.com/tmc/langchaingo/llms/ollama":          ModeJSON,
+	"github.com/tmc/langchaingo/llms/maritaca":        ModeJSON,
+}
+
+// Generate asks the model to reply to the messages with a value of type T. The
+// JSON schema of T is generated with jsonschema.GenerateSchemaForType, and the
+// reply is validated against it before being decoded.
+func Generate[T any](
+	ctx context.Context,
+	model llms.Model,
+	messages []llms.MessageContent,
+	options ...Option,
+) (T, error) {
+	var result T
+
+	opts := defaultOptions()
+	for _, opt := range options {
+		opt(&opts)
+	}
+
+	def, wrapped, err := schemaFor[T]()
+	if err != nil {
+		return result, err
+	}
+
+	callOptions := append([]llms.CallOption{}, opts.CallOptions...)
+	switch mode := ResolveMode(model, opts.Mode, def); mode {
+	case ModeStrictTool, ModeTool:
+		callOptions = append(callOptions,
+			llms.WithTools([]llms.Tool{{
+				Type: "function",
+				Function: &llms.FunctionDefinition{
+					Name:        opts.Name,
+					Description: opts.Description,
+					Parameters:  def,
+					Strict:      mode == ModeStrictTool,
+				},
+			}}),
+			llms.WithToolChoice(llms.ToolChoice{
+				Type:     "function",
+				Function: &llms.FunctionReference{Name: opts.Name},
+			}),
+		)
+	case ModeJSON:
+		messages, err = withInstructions(messages, def)
+		callOptions = append(callOptions, llms.WithJSONMode())
+	default:
+		messages, err = withInstructions(messages, def)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	resp, err := model.GenerateContent(ctx, messages, callOptions...)
+	if err != nil {
+		return result, err
+	}
+	output, err := outputText(resp, opts.Name)
+	if err != nil {
+		return result, err
+	}
+
+	return decode[T](output, def, wrapped)
+}
+
+// GenerateFromSinglePrompt is a convenience function for calling Generate with
+// a single string prompt.
+func GenerateFromSinglePrompt[T any](ctx context.Context, model llms.Model, prompt string, options ...Option) (T, error) {
+	return Generate[T](ctx, model, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, prompt),
+	}, options...)
+}
+
+// ResolveMode returns the mode Generate uses for the model. Unless a mode is
+// requested, the mode is picked from the package the model is implemented in.
+// Strict tool calls are only used when the schema is compatible with them.
+func ResolveMode(model llms.Model, requested Mode, def *jsonschema.Definition) Mode {
+	mode := requested
+	if mode == ModeAuto {
+		mode = ModePrompt
+		if t := reflect.TypeOf(model); t != nil {
+			if t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			if m, ok := providerModes[t.PkgPath()]; ok {
+				mode = m
+			}
+		}
+	}
+	if mode == ModeStrictTool && !isStrictCompatible(def) {
+		mode = ModeTool
+	}
+	return mode
+}
+
+// schemaFor returns the schema of T, wrapped in an object if T is not encoded
+// as a JSON object.
+func schemaFor[T any]() (*jsonschema.Definition, bool, error) {
+	def, err := jsonschema.GenerateSchemaForType(new(T))
+	if err != nil {
+		return nil, false, err
+	}
+	if def.Type == jsonschema.Object {
+		return def, false, nil
+	}
+	return &jsonschema.Definition{
+		Type:                 jsonschema.Object,
+		Properties:           map[string]jsonschema.Definition{_wrappedKey: *def},
+		Required:             []string{_wrappedKey},
+		AdditionalProperties: false,
+	}, true, nil
+}
+
+// isStrictCompatible reports whether OpenAI strict mode accepts the schema: all
+// properties of every object must be required, and no object may allow
+// additional properties.
+func isStrictCompatible(def *jsonschema.Definition) bool {
+	if def == nil {
+		return true
+	}
+	if def.Type == jsonschema.Object {
+		if additional, ok := def.AdditionalProperties.(bool); !ok || additional {
+			return false
+		}
+		if len(def.Required) != len(def.Properties) {
+			return false
+		}
+	}
+	for _, prop := range def.Properties {
+		if !isStrictCompatible(&prop) {
+			return false
+		}
+	}
+	return isStrictCompatible(def.Items)
+}
+
+// withInstructions returns a copy of messages with the schema instructions
+// added to the last human message, or to a new human message.
+func withInstructions(messages []llms.MessageContent, def *jsonschema.Definition) ([]llms.MessageContent, error) {
+	schema, err := json.MarshalIndent(def, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	instructions := llms.TextContent{Text: fmt.Sprintf(_instructions, schema)}
+
+	result := append([]llms.MessageContent{}, messages...)
+	if n := len(result); n > 0 && result[n-1].Role == llms.ChatMessageTypeHuman {
+		last := result[n-1]
+		last.Parts = append(append([]llms.ContentPart{}, last.Parts...), instructions)
+		result[n-1] = last
+		return result, nil
+	}
+	return append(result, llms.MessageContent{
+		Role:  llms.ChatMessageTypeHuman,
+		Parts: []llms.ContentPart{instructions},
+	}), nil
+}
+
+// outputText returns the arguments of the call to the named tool, or the
+// content of the first choice if the model did not call it.
+func outputText(resp *llms.ContentResponse, name string) (string, error) {
+	if resp == nil || len(resp.Choices) == 0 {
+		return "", ErrEmptyResponse
+	}
+	choice := resp.Choices[0]
+	for _, toolCall := range choice.ToolCalls {
+		if toolCall.FunctionCall != nil && toolCall.FunctionCall.Name == name {
+			return toolCall.FunctionCall.Arguments, nil
+		}
+	}
+	if choice.FuncCall != nil && choice.FuncCall.Name == name {
+		return choice.FuncCall.Arguments, nil
+	}
+	return choice.Content, nil
+}
+
+func decode[T any](output string, def *jsonschema.Definition, wrapped bool) (T, error) {
+	var result T
+
+	text, err := outputparser.ExtractJSON(output)
+	if err != nil {
+		return result, fmt.Errorf("%w: %w", ErrInvalidOutput, err)
+	}
+	decoder := json.NewDecoder(strings.NewReader(text))
+	decoder.UseNumber()
+	var data any
+	if err := decoder.Decode(&data); err != nil {
+		return result, fmt.Errorf("%w: %w", ErrInvalidOutput, err)
+	}
+	if err := def.Validate(data); err != nil {
+		return result, fmt.Errorf("%w: %w", ErrInvalidOutput, err)
+	}
+
+	if wrapped {
+		var w struct {
+			Value json.RawMessage `json:"value"`
+		}
+		if err := json.Unmarshal([]byte(text), &w); err != nil {
+			return result, fmt.Errorf("%w: %w", ErrInvalidOutput, err)
+		}
+		text = string(w.Value)
+	}
+	if err := json.Unmarshal([]byte(text), &result); err != nil {
+		return result, fmt.Errorf("%w: %w", ErrInvalidOutput, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,112 @@
+package structured
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/jsonschema"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic"
+	"github.com/tmc/langchaingo/llms/mistral"
+)
+
+type recipe struct {
+	Name        string   `json:"name"`
+	Ingredients []string `json:"ingredients"`
+	Minutes     int      `json:"minutes"            describe:"preparation time"`
+	Difficulty  string   `json:"difficulty"         enum:"easy,hard"`
+	Notes       string   `json:"notes,omitempty"`
+}
+
+type mockModel struct {
+	response *llms.ContentResponse
+	messages []llms.MessageContent
+	options  llms.CallOptions
+}
+
+func (m *mockModel) GenerateContent(
+	_ context.Context, messages []llms.MessageContent, options ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	m.messages = messages
+	for _, opt := range options {
+		opt(&m.options)
+	}
+	return m.response, nil
+}
+
+func (m *mockModel) Call(ctx context.Context, prompt string, options ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, options...)
+}
+
+func TestGenerateTool(t *testing.T) {
+	t.Parallel()
+
+	model := &mockModel{response: &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		ToolCalls: []llms.ToolCall{{
+			Type: "function",
+			FunctionCall: &llms.FunctionCall{
+				Name:      "respond",
+				Arguments: `{"name":"Toast","ingredients":["bread"],"minutes":3,"difficulty":"easy"}`,
+			},
+		}},
+	}}}}
+
+	result, err := GenerateFromSinglePrompt[recipe](context.Background(), model, "A quick recipe", WithMode(ModeStrictTool))
+	require.NoError(t, err)
+	assert.Equal(t, recipe{Name: "Toast", Ingredients: []string{"bread"}, Minutes: 3, Difficulty: "easy"}, result)
+
+	require.Len(t, model.options.Tools, 1)
+	// The optional notes field makes the schema incompatible with strict mode.
+	assert.False(t, model.options.Tools[0].Function.Strict)
+	assert.Equal(t, llms.ToolChoice{Type: "function", Function: &llms.FunctionReference{Name: "respond"}},
+		model.options.ToolChoice)
+}
+
+func TestGenerateJSON(t *testing.T) {
+	t.Parallel()
+
+	model := &mockModel{response: &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		Content: "Sure!\n```json\n{\"value\": [1, 2, 3]}\n```",
+	}}}}
+
+	result, err := GenerateFromSinglePrompt[[]int](context.Background(), model, "Count to 3", WithMode(ModeJSON))
+	require.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, result)
+
+	assert.True(t, model.options.JSONMode)
+	require.Len(t, model.messages, 1)
+	require.Len(t, model.messages[0].Parts, 2)
+	assert.Contains(t, model.messages[0].Parts[1].(llms.TextContent).Text, `"value"`)
+}
+
+func TestGenerateValidation(t *testing.T) {
+	t.Parallel()
+
+	model := &mockModel{response: &llms.ContentResponse{Choices: []*llms.ContentChoice{{
+		Content: `{"name":"Toast","ingredients":["bread"],"minutes":3,"difficulty":"medium"}`,
+	}}}}
+
+	_, err := GenerateFromSinglePrompt[recipe](context.Background(), model, "A quick recipe")
+	require.ErrorIs(t, err, ErrInvalidOutput)
+	require.ErrorIs(t, err, jsonschema.ErrValidation)
+}
+
+func TestResolveMode(t *testing.T) {
+	t.Parallel()
+
+	strict := &jsonschema.Definition{Type: jsonschema.Object, AdditionalProperties: false}
+	assert.Equal(t, ModePrompt, ResolveMode(&mockModel{}, ModeAuto, strict))
+	assert.Equal(t, ModeJSON, ResolveMode(&mockModel{}, ModeJSON, strict))
+	assert.Equal(t, ModeStrictTool, ResolveMode(&mockModel{}, ModeStrictTool, strict))
+	assert.Equal(t, ModeTool, ResolveMode(&mockModel{}, ModeStrictTool, &jsonschema.Definition{Type: jsonschema.Object}))
+
+	// Anthropic and Mistral ignore the tool choice and have no JSON mode.
+	anthropicModel, err := anthropic.New(anthropic.WithToken("test"))
+	require.NoError(t, err)
+	assert.Equal(t, ModePrompt, ResolveMode(anthropicModel, ModeAuto, strict))
+	mistralModel, err := mistral.New(mistral.WithAPIKey("test"))
+	require.NoError(t, err)
+	assert.Equal(t, ModePrompt, ResolveMode(mistralModel, ModeAuto, strict))
+}
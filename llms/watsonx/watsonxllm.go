@@ -31,6 +31,7 @@ func (wx *LLM) Call(ctx context.Context, prompt string, options ...llms.CallOpti
 // GenerateContent implements the Model interface.
 func (wx *LLM) GenerateContent(ctx context.Context, messages []llms.MessageContent, options ...llms.CallOption) (*llms.ContentResponse, error) { //nolint: lll, cyclop, whitespace
 
+	ctx = callbacks.StartRun(ctx, callbacks.RunTypeLLM, "watsonx")
 	if wx.CallbacksHandler != nil {
 		wx.CallbacksHandler.HandleLLMGenerateContentStart(ctx, messages)
 	}
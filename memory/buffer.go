@@ -22,6 +22,10 @@ type ConversationBuffer struct {
 	HumanPrefix    string
 	AIPrefix       string
 	MemoryKey      string
+
+	// countTokens counts the tokens of the buffer for the memories with a
+	// token limit. If nil, llms.CountTokens is used.
+	countTokens func(text string) int
 }
 
 // Statically assert that ConversationBuffer implement the memory interface.
@@ -107,6 +111,15 @@ func (m *ConversationBuffer) GetMemoryKey(context.Context) string {
 	return m.MemoryKey
 }
 
+// numTokens returns the number of tokens of text, counted with the token
+// counter of the buffer.
+func (m *ConversationBuffer) numTokens(text string) int {
+	if m.countTokens != nil {
+		return m.countTokens(text)
+	}
+	return llms.CountTokens("", text)
+}
+
 func GetInputValue(inputValues map[string]any, inputKey string) (string, error) {
 	// If the input key is set, return the value in the inputValues with the input key.
 	if inputKey != "" {
@@ -55,6 +55,16 @@ func WithMemoryKey(memoryKey string) ConversationBufferOption {
 	}
 }
 
+// WithTokenCounter is an option for specifying the function counting the
+// tokens of the buffer in the memories with a token limit, such as the
+// ConversationSummaryBuffer and the ConversationTokenBuffer. By default,
+// llms.CountTokens is used.
+func WithTokenCounter(countTokens func(text string) int) ConversationBufferOption {
+	return func(b *ConversationBuffer) {
+		b.countTokens = countTokens
+	}
+}
+
 func applyBufferOptions(opts ...ConversationBufferOption) *ConversationBuffer {
 	m := &ConversationBuffer{
 		ReturnMessages: false,
@@ -5,5 +5,9 @@ a variety of implementations for storing and retrieving that data.
 The main components of this package are:
 - ChatMessageHistory: a struct that stores chat messages.
 - ConversationBuffer: a simple form of memory that remembers previous conversational back and forth directly.
+- ConversationSummary: a memory that uses an LLM to keep a running summary of the conversation.
+- ConversationSummaryBuffer: a memory that keeps recent messages verbatim and summarizes older ones.
+- VectorStoreRetriever: a memory that retrieves the past exchanges most relevant to the input from a vector store.
+- ConversationEntity: a memory that uses an LLM to keep a summary of the entities mentioned in the conversation.
 */
 package memory
@@ -0,0 +1,321 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	// defaultEntityHistoryPairs is the default number of recent exchanges used as
+	// context when extracting and summarizing entities.
+	defaultEntityHistoryPairs = 3
+	// noEntitiesOutput is the output of the extraction prompt when the input
+	// mentions no entities.
+	noEntitiesOutput = "NONE"
+)
+
+const _defaultEntityExtractionTemplate = `You are an AI assistant reading the transcript of a conversation between an AI and a human. Extract all of the proper nouns from the last line of conversation. As a guideline, a proper noun is generally capitalized. You should definitely extract all names and places.
+
+The conversation history is provided just in case of a coreference (e.g. "What do you know about him" where "him" is defined in a previous line) -- ignore items mentioned there that are not in the last line.
+
+Return the output as a single comma-separated list, or NONE if there is nothing of note to return (e.g. the user is just issuing a greeting or having a simple conversation).
+
+EXAMPLE
+Conversation history:
+Person #1: my grandma loves the Eiffel Tower
+AI: "That's great, it's a beautiful monument."
+Last line:
+Person #1: she visited it with her friend Anna from Lyon last summer
+Output: Eiffel Tower, Anna, Lyon
+END OF EXAMPLE
+
+Conversation history (for reference only):
+{{.history}}
+Last line of conversation (for extraction):
+Human: {{.input}}
+
+Output:`
+
+const _defaultEntitySummarizationTemplate = `You are an AI assistant helping a human keep track of facts about relevant people, places, and concepts in their life. Update the summary of the provided entity in the "Entity" section based on the last line of your conversation with the human. If you are writing the summary for the first time, return a single sentence.
+The update should only include facts that are relayed in the last line of conversation about the provided entity, and should only contain facts about the provided entity.
+
+If there is no new information about the provided entity or the information is not worth noting (not an important or relevant fact to remember long-term), return the existing summary unchanged.
+
+Full conversation history (for context):
+{{.history}}
+
+Entity to summarize:
+{{.entity}}
+
+Existing summary of {{.entity}}:
+{{.summary}}
+
+Last line of conversation:
+Human: {{.input}}
+Updated summary:`
+
+// ConversationEntity is a memory that uses an LLM to extract the named entities
+// mentioned in the conversation and to maintain a summary of what is known
+// about each of them in an EntityStore. Besides the recent messages, only the
+// summaries of the entities mentioned in the current input are loaded.
+type ConversationEntity struct {
+	ConversationBuffer
+	LLM         llms.Model
+	EntityStore EntityStore
+
+	EntityExtractionPrompt    prompts.PromptTemplate
+	EntitySummarizationPrompt prompts.PromptTemplate
+
+	// HistoryPairs is the number of recent exchanges loaded as history and used
+	// as context for the LLM.
+	HistoryPairs int
+	// EntitiesKey is the key under which the entity summaries are loaded.
+	EntitiesKey string
+
+	mu sync.Mutex
+	// entityCache holds the entities extracted from entityCacheInput, the last
+	// loaded input, so that they are not extracted again when the exchange
+	// with this input is saved.
+	entityCache      []string
+	entityCacheInput string
+}
+
+// Statically assert that ConversationEntity implement the memory interface.
+var _ schema.Memory = &ConversationEntity{}
+
+// NewConversationEntity is a function for creating a new entity memory. If
+// store is nil, the entities are kept in memory.
+func NewConversationEntity(
+	llm llms.Model,
+	store EntityStore,
+	options ...ConversationBufferOption,
+) *ConversationEntity {
+	if store == nil {
+		store = NewInMemoryEntityStore()
+	}
+
+	return &ConversationEntity{
+		ConversationBuffer: *applyBufferOptions(options...),
+		LLM:                llm,
+		EntityStore:        store,
+		EntityExtractionPrompt: prompts.NewPromptTemplate(
+			_defaultEntityExtractionTemplate, []string{"history", "input"},
+		),
+		EntitySummarizationPrompt: prompts.NewPromptTemplate(
+			_defaultEntitySummarizationTemplate, []string{"history", "entity", "summary", "input"},
+		),
+		HistoryPairs: defaultEntityHistoryPairs,
+		EntitiesKey:  "entities",
+	}
+}
+
+// MemoryVariables returns the memory key and the entities key.
+func (m *ConversationEntity) MemoryVariables(context.Context) []string {
+	return []string{m.EntitiesKey, m.MemoryKey}
+}
+
+// LoadMemoryVariables extracts the entities mentioned in the input and returns
+// their summaries under EntitiesKey, together with the recent messages under
+// MemoryKey. If ReturnMessages is set to true the history is a slice of
+// llms.ChatMessage and the entities a map from entity name to summary.
+// Otherwise, both are returned as strings.
+func (m *ConversationEntity) LoadMemoryVariables(
+	ctx context.Context, inputs map[string]any,
+) (map[string]any, error) {
+	input, err := GetInputValue(m.filterInputs(inputs), m.InputKey)
+	if err != nil {
+		return nil, err
+	}
+	messages, err := m.recentMessages(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entities, err := m.extractEntities(ctx, messages, input)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	m.entityCache, m.entityCacheInput = entities, input
+	m.mu.Unlock()
+
+	summaries := make(map[string]string, len(entities))
+	lines := make([]string, 0, len(entities))
+	for _, entity := range entities {
+		summary, err := m.EntityStore.Get(ctx, entity)
+		if err != nil {
+			return nil, err
+		}
+		summaries[entity] = summary
+		lines = append(lines, fmt.Sprintf("%s: %s", entity, summary))
+	}
+
+	if m.ReturnMessages {
+		return map[string]any{
+			m.EntitiesKey: summaries,
+			m.MemoryKey:   messages,
+		}, nil
+	}
+
+	bufferString, err := llms.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		m.EntitiesKey: strings.Join(lines, "\n"),
+		m.MemoryKey:   bufferString,
+	}, nil
+}
+
+// SaveContext uses ConversationBuffer method for saving context, then updates
+// the summary of every entity mentioned in the input.
+func (m *ConversationEntity) SaveContext(
+	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
+) error {
+	inputValues = m.filterInputs(inputValues)
+	err := m.ConversationBuffer.SaveContext(ctx, inputValues, outputValues)
+	if err != nil {
+		return err
+	}
+	input, err := GetInputValue(inputValues, m.InputKey)
+	if err != nil {
+		return err
+	}
+	messages, err := m.recentMessages(ctx)
+	if err != nil {
+		return err
+	}
+
+	// The entities of the input are only extracted again if it is not the
+	// last loaded one, such as when SaveContext is called without
+	// LoadMemoryVariables or concurrently with another exchange.
+	var entities []string
+	m.mu.Lock()
+	if m.entityCache != nil && m.entityCacheInput == input {
+		entities = m.entityCache
+		m.entityCache, m.entityCacheInput = nil, ""
+	}
+	m.mu.Unlock()
+	if entities == nil {
+		entities, err = m.extractEntities(ctx, messages, input)
+		if err != nil {
+			return err
+		}
+	}
+
+	history, err := llms.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return err
+	}
+	for _, entity := range entities {
+		if err := m.updateEntity(ctx, history, entity, input); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Clear clears the chat history and the entity store.
+func (m *ConversationEntity) Clear(ctx context.Context) error {
+	m.mu.Lock()
+	m.entityCache, m.entityCacheInput = nil, ""
+	m.mu.Unlock()
+
+	if err := m.ConversationBuffer.Clear(ctx); err != nil {
+		return err
+	}
+	return m.EntityStore.Clear(ctx)
+}
+
+func (m *ConversationEntity) updateEntity(ctx context.Context, history, entity, input string) error {
+	summary, err := m.EntityStore.Get(ctx, entity)
+	if err != nil {
+		return err
+	}
+	prompt, err := m.EntitySummarizationPrompt.Format(map[string]any{
+		"history": history,
+		"entity":  entity,
+		"summary": summary,
+		"input":   input,
+	})
+	if err != nil {
+		return err
+	}
+
+	newSummary, err := llms.GenerateFromSinglePrompt(ctx, m.LLM, prompt)
+	if err != nil {
+		return err
+	}
+	return m.EntityStore.Set(ctx, entity, strings.TrimSpace(newSummary))
+}
+
+func (m *ConversationEntity) extractEntities(
+	ctx context.Context, messages []llms.ChatMessage, input string,
+) ([]string, error) {
+	history, err := llms.GetBufferString(messages, m.HumanPrefix, m.AIPrefix)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := m.EntityExtractionPrompt.Format(map[string]any{
+		"history": history,
+		"input":   input,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := llms.GenerateFromSinglePrompt(ctx, m.LLM, prompt)
+	if err != nil {
+		return nil, err
+	}
+	return parseEntities(output), nil
+}
+
+func (m *ConversationEntity) recentMessages(ctx context.Context) ([]llms.ChatMessage, error) {
+	messages, err := m.ChatHistory.Messages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if n := m.HistoryPairs * defaultMessageSize; len(messages) > n {
+		messages = messages[len(messages)-n:]
+	}
+	return messages, nil
+}
+
+// filterInputs removes the memory variables from the inputs, as they hold the
+// values loaded previously and not user input.
+func (m *ConversationEntity) filterInputs(inputs map[string]any) map[string]any {
+	filtered := make(map[string]any, len(inputs))
+	for k, v := range inputs {
+		if k != m.MemoryKey && k != m.EntitiesKey {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
+
+// parseEntities parses the comma separated output of the extraction prompt.
+func parseEntities(output string) []string {
+	output = strings.TrimSpace(output)
+	entities := make([]string, 0)
+	if strings.EqualFold(output, noEntitiesOutput) {
+		return entities
+	}
+
+	seen := make(map[string]bool)
+	for _, entity := range strings.Split(output, ",") {
+		entity = strings.TrimSpace(entity)
+		if entity == "" || seen[entity] {
+			continue
+		}
+		seen[entity] = true
+		entities = append(entities, entity)
+	}
+	return entities
+}
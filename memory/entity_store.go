@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+// EntityStore is the interface for storing the summaries kept by the entity
+// memory, keyed by entity name.
+type EntityStore interface {
+	// Get returns the summary of an entity, or an empty string if the entity is
+	// not in the store.
+	Get(ctx context.Context, entity string) (string, error)
+	// Set stores the summary of an entity.
+	Set(ctx context.Context, entity string, summary string) error
+	// Delete removes an entity from the store.
+	Delete(ctx context.Context, entity string) error
+	// Exists reports whether an entity is in the store.
+	Exists(ctx context.Context, entity string) (bool, error)
+	// Clear removes all entities from the store.
+	Clear(ctx context.Context) error
+}
+
+// InMemoryEntityStore is an entity store that keeps the summaries in a map.
+type InMemoryEntityStore struct {
+	mu       sync.RWMutex
+	entities map[string]string
+}
+
+// Statically assert that InMemoryEntityStore implement the entity store interface.
+var _ EntityStore = &InMemoryEntityStore{}
+
+// NewInMemoryEntityStore creates a new, empty, in memory entity store.
+func NewInMemoryEntityStore() *InMemoryEntityStore {
+	return &InMemoryEntityStore{
+		entities: make(map[string]string),
+	}
+}
+
+// Get returns the summary of an entity.
+func (s *InMemoryEntityStore) Get(_ context.Context, entity string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.entities[entity], nil
+}
+
+// Set stores the summary of an entity.
+func (s *InMemoryEntityStore) Set(_ context.Context, entity string, summary string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entities[entity] = summary
+	return nil
+}
+
+// Delete removes an entity from the store.
+func (s *InMemoryEntityStore) Delete(_ context.Context, entity string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entities, entity)
+	return nil
+}
+
+// Exists reports whether an entity is in the store.
+func (s *InMemoryEntityStore) Exists(_ context.Context, entity string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.entities[entity]
+	return ok, nil
+}
+
+// Clear removes all entities from the store.
+func (s *InMemoryEntityStore) Clear(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entities = make(map[string]string)
+	return nil
+}
@@ -0,0 +1,86 @@
+package memory
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms/fake"
+)
+
+func TestConversationEntity(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	llm := fake.NewFakeLLM([]string{
+		"Deven, Sam",
+		"Deven is working on a hackathon project with Sam.",
+		"Sam is working on a hackathon project with Deven.",
+		"Sam",
+	})
+	m := NewConversationEntity(llm, nil)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{"input": "Deven & Sam are working on a hackathon project"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"entities": "Deven: \nSam: ", "history": ""}, result)
+
+	err = m.SaveContext(ctx,
+		map[string]any{"input": "Deven & Sam are working on a hackathon project"},
+		map[string]any{"output": "That sounds like a great project!"},
+	)
+	require.NoError(t, err)
+
+	summary, err := m.EntityStore.Get(ctx, "Deven")
+	require.NoError(t, err)
+	assert.Equal(t, "Deven is working on a hackathon project with Sam.", summary)
+
+	result, err = m.LoadMemoryVariables(ctx, map[string]any{"input": "What is Sam doing?"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"entities": "Sam: Sam is working on a hackathon project with Deven.",
+		"history":  "Human: Deven & Sam are working on a hackathon project\nAI: That sounds like a great project!",
+	}, result)
+
+	require.NoError(t, m.Clear(ctx))
+	exists, err := m.EntityStore.Exists(ctx, "Sam")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestConversationEntitySaveOtherInput(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	llm := fake.NewFakeLLM([]string{
+		"Deven",
+		"Sam",
+		"Sam is in Paris.",
+	})
+	m := NewConversationEntity(llm, nil)
+
+	_, err := m.LoadMemoryVariables(ctx, map[string]any{"input": "Deven is here"})
+	require.NoError(t, err)
+
+	// The entities of the saved input are extracted, not those of the loaded
+	// one.
+	err = m.SaveContext(ctx,
+		map[string]any{"input": "Sam is in Paris"},
+		map[string]any{"output": "Nice!"},
+	)
+	require.NoError(t, err)
+
+	summary, err := m.EntityStore.Get(ctx, "Sam")
+	require.NoError(t, err)
+	assert.Equal(t, "Sam is in Paris.", summary)
+	exists, err := m.EntityStore.Exists(ctx, "Deven")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestParseEntities(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, []string{}, parseEntities(" NONE\n"))
+	assert.Equal(t, []string{"Paris", "Anna"}, parseEntities("Paris, Anna,, Paris"))
+}
@@ -0,0 +1,183 @@
+// Package postgresql adds support for
+// chat message history using PostgreSQL.
+package postgresql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// PGXConn represents both a pgx.Conn and pgxpool.Pool conn.
+type PGXConn interface {
+	Ping(ctx context.Context) error
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, arguments ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, arguments ...any) (pgx.Rows, error)
+}
+
+// ChatMessageHistory is a chat message history stored in a PostgreSQL table.
+// Every message is stored as a JSON document in its own row, so that tool
+// calls and tool responses are kept with full fidelity.
+type ChatMessageHistory struct {
+	connURL   string
+	conn      PGXConn
+	tableName string
+	sessionID string
+	limit     int
+	// ownsConn is set when the connection was opened from connURL.
+	ownsConn bool
+}
+
+// Statically assert that ChatMessageHistory implement the chat message history interface.
+var _ schema.ChatMessageHistory = &ChatMessageHistory{}
+
+// NewPostgreSQLChatMessageHistory creates a new ChatMessageHistory using chat message options,
+// creating the messages table if it does not exist.
+func NewPostgreSQLChatMessageHistory(
+	ctx context.Context,
+	options ...ChatMessageHistoryOption,
+) (*ChatMessageHistory, error) {
+	h, err := applyChatOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.conn == nil {
+		h.conn, err = pgx.Connect(ctx, h.connURL)
+		if err != nil {
+			return nil, err
+		}
+		h.ownsConn = true
+	}
+	if err := h.conn.Ping(ctx); err != nil {
+		return nil, err
+	}
+
+	table := h.table()
+	index := pgx.Identifier{h.tableName + "_session_id_idx"}.Sanitize()
+	if _, err := h.conn.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id BIGSERIAL PRIMARY KEY,
+	session_id TEXT NOT NULL,
+	message JSONB NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+CREATE INDEX IF NOT EXISTS %s ON %s (session_id, id);`, table, index, table)); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// Messages returns the messages of the session, oldest first. If a limit is
+// set, only the most recent messages within the limit are returned.
+func (h *ChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMessage, error) {
+	query := fmt.Sprintf("SELECT message FROM %s WHERE session_id = $1 ORDER BY id ASC", h.table())
+	args := []any{h.sessionID}
+	if h.limit > 0 {
+		query = fmt.Sprintf(
+			"SELECT message FROM (SELECT id, message FROM %s WHERE session_id = $1 ORDER BY id DESC LIMIT $2) AS m ORDER BY id ASC",
+			h.table(),
+		)
+		args = append(args, h.limit)
+	}
+
+	rows, err := h.conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []llms.ChatMessage{}
+	for rows.Next() {
+		var data []byte
+		if err := rows.Scan(&data); err != nil {
+			return nil, err
+		}
+		m := llms.ChatMessageModel{}
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, err
+		}
+		// Messages of unknown types, logged by ToChatMessage, are skipped.
+		if message := m.ToChatMessage(); message != nil {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages, rows.Err()
+}
+
+// AddAIMessage adds an AIMessage to the chat message history.
+func (h *ChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.AIChatMessage{Content: text})
+}
+
+// AddUserMessage adds a user to the chat message history.
+func (h *ChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.HumanChatMessage{Content: text})
+}
+
+// AddMessage adds a message to the chat message history.
+func (h *ChatMessageHistory) AddMessage(ctx context.Context, message llms.ChatMessage) error {
+	data, err := json.Marshal(llms.ConvertChatMessageToModel(message))
+	if err != nil {
+		return err
+	}
+
+	_, err = h.conn.Exec(ctx,
+		fmt.Sprintf("INSERT INTO %s (session_id, message) VALUES ($1, $2)", h.table()),
+		h.sessionID, data,
+	)
+	return err
+}
+
+// Clear removes all messages of the session.
+func (h *ChatMessageHistory) Clear(ctx context.Context) error {
+	_, err := h.conn.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE session_id = $1", h.table()), h.sessionID)
+	return err
+}
+
+// SetMessages replaces the messages of the session in a single transaction.
+func (h *ChatMessageHistory) SetMessages(ctx context.Context, messages []llms.ChatMessage) error {
+	tx, err := h.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx) //nolint:errcheck
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE session_id = $1", h.table()), h.sessionID); err != nil {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	insert := fmt.Sprintf("INSERT INTO %s (session_id, message) VALUES ($1, $2)", h.table())
+	for _, message := range messages {
+		data, err := json.Marshal(llms.ConvertChatMessageToModel(message))
+		if err != nil {
+			return err
+		}
+		batch.Queue(insert, h.sessionID, data)
+	}
+	if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Close closes the connection, if it was opened by the chat message history.
+func (h *ChatMessageHistory) Close(ctx context.Context) error {
+	if conn, ok := h.conn.(*pgx.Conn); ok && h.ownsConn {
+		return conn.Close(ctx)
+	}
+	return nil
+}
+
+func (h *ChatMessageHistory) table() string {
+	return pgx.Identifier{h.tableName}.Sanitize()
+}
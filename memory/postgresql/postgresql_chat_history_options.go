@@ -0,0 +1,78 @@
+package postgresql
+
+import (
+	"errors"
+)
+
+const (
+	// DefaultTableName is the default name of the messages table.
+	DefaultTableName = "langchaingo_chat_history"
+)
+
+var (
+	errInvalidConnection = errors.New("invalid postgresql connection option")
+	errInvalidSessionID  = errors.New("invalid postgresql session id option")
+)
+
+// ChatMessageHistoryOption is a function for creating new chat message history
+// with other than the default values.
+type ChatMessageHistoryOption func(m *ChatMessageHistory)
+
+// WithConnectionURL is an option for specifying the PostgreSQL connection URL.
+// Either this or WithConn must be used.
+func WithConnectionURL(connectionURL string) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.connURL = connectionURL
+	}
+}
+
+// WithConn is an option for specifying the PostgreSQL connection.
+// From pgx doc: it is not safe for concurrent usage. Use a connection pool to
+// manage access to multiple database connections from multiple goroutines.
+func WithConn(conn PGXConn) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.conn = conn
+	}
+}
+
+// WithSessionID is an arbitrary key that is used to store the messages of a single chat session,
+// like user name, email, chat id etc. Must be set.
+func WithSessionID(sessionID string) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.sessionID = sessionID
+	}
+}
+
+// WithTableName is an option for specifying the messages table name.
+func WithTableName(name string) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.tableName = name
+	}
+}
+
+// WithLimit is an option for only returning the most recent messages of the
+// session. All messages are kept in the table.
+func WithLimit(limit int) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.limit = limit
+	}
+}
+
+func applyChatOptions(options ...ChatMessageHistoryOption) (*ChatMessageHistory, error) {
+	h := &ChatMessageHistory{
+		tableName: DefaultTableName,
+	}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	if h.conn == nil && h.connURL == "" {
+		return nil, errInvalidConnection
+	}
+	if h.sessionID == "" {
+		return nil, errInvalidSessionID
+	}
+
+	return h, nil
+}
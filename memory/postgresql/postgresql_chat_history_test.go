@@ -0,0 +1,111 @@
+package postgresql
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func getPostgreSQLURL(t *testing.T) string {
+	t.Helper()
+
+	if url := os.Getenv("POSTGRESQL_CONNECTION_STRING"); url != "" {
+		return url
+	}
+
+	ctx := context.Background()
+	container, err := tcpostgres.RunContainer(
+		ctx,
+		testcontainers.WithImage("docker.io/postgres:16-alpine"),
+		tcpostgres.WithDatabase("db_test"),
+		tcpostgres.WithUsername("user"),
+		tcpostgres.WithPassword("passw0rd!"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(30*time.Second)),
+	)
+	if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
+		t.Skip("Docker not available")
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, container.Terminate(context.Background()))
+	})
+
+	url, err := container.ConnectionString(ctx, "sslmode=disable")
+	require.NoError(t, err)
+	return url
+}
+
+func TestPostgreSQLChatMessageHistory(t *testing.T) {
+	t.Parallel()
+
+	url := getPostgreSQLURL(t)
+	ctx := context.Background()
+
+	_, err := NewPostgreSQLChatMessageHistory(ctx, WithSessionID("test"))
+	assert.Equal(t, errInvalidConnection, err)
+
+	_, err = NewPostgreSQLChatMessageHistory(ctx, WithConnectionURL(url))
+	assert.Equal(t, errInvalidSessionID, err)
+
+	history, err := NewPostgreSQLChatMessageHistory(ctx, WithConnectionURL(url), WithSessionID("test-session"))
+	require.NoError(t, err)
+	defer history.Close(ctx)
+
+	toolCall := llms.AIChatMessage{ToolCalls: []llms.ToolCall{{
+		ID:           "call_1",
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`},
+	}}}
+	require.NoError(t, history.AddUserMessage(ctx, "What's the weather in Paris?"))
+	require.NoError(t, history.AddMessage(ctx, toolCall))
+	require.NoError(t, history.AddMessage(ctx, llms.ToolChatMessage{ID: "call_1", Content: "Sunny"}))
+	require.NoError(t, history.AddAIMessage(ctx, "It is sunny."))
+	// Messages of unknown types are skipped.
+	_, err = history.conn.Exec(ctx,
+		fmt.Sprintf("INSERT INTO %s (session_id, message) VALUES ($1, $2)", history.table()),
+		"test-session", []byte(`{"type":"unknown","data":{"content":"?"}}`))
+	require.NoError(t, err)
+
+	messages, err := history.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "What's the weather in Paris?"},
+		toolCall,
+		llms.ToolChatMessage{ID: "call_1", Content: "Sunny"},
+		llms.AIChatMessage{Content: "It is sunny."},
+	}, messages)
+
+	windowed, err := NewPostgreSQLChatMessageHistory(ctx,
+		WithConnectionURL(url), WithSessionID("test-session"), WithLimit(3))
+	require.NoError(t, err)
+	defer windowed.Close(ctx)
+	messages, err = windowed.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.ToolChatMessage{ID: "call_1", Content: "Sunny"},
+		llms.AIChatMessage{Content: "It is sunny."},
+	}, messages)
+
+	require.NoError(t, history.SetMessages(ctx, []llms.ChatMessage{llms.SystemChatMessage{Content: "Be brief."}}))
+	messages, err = history.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{llms.SystemChatMessage{Content: "Be brief."}}, messages)
+
+	require.NoError(t, history.Clear(ctx))
+	messages, err = history.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
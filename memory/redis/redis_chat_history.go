@@ -0,0 +1,158 @@
+// Package redis adds support for
+// chat message history using Redis.
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/redis/rueidis"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ChatMessageHistory is a chat message history stored in a Redis list. Every
+// message is stored as a JSON document, so that tool calls and tool responses
+// are kept with full fidelity.
+type ChatMessageHistory struct {
+	url    string
+	client rueidis.Client
+	// ownsClient is set when the client was created from the URL, and not
+	// given with WithClient.
+	ownsClient bool
+	keyPrefix  string
+	sessionID  string
+	ttl        int64
+	limit      int64
+}
+
+// Statically assert that ChatMessageHistory implement the chat message history interface.
+var _ schema.ChatMessageHistory = &ChatMessageHistory{}
+
+// NewRedisChatMessageHistory creates a new ChatMessageHistory using chat message options.
+func NewRedisChatMessageHistory(options ...ChatMessageHistoryOption) (*ChatMessageHistory, error) {
+	h, err := applyChatOptions(options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.client == nil {
+		clientOption, err := rueidis.ParseURL(h.url)
+		if err != nil {
+			return nil, err
+		}
+		h.client, err = rueidis.NewClient(clientOption)
+		if err != nil {
+			return nil, err
+		}
+		h.ownsClient = true
+	}
+
+	return h, nil
+}
+
+// Messages returns the messages of the session, oldest first. If a limit is
+// set, only the most recent messages within the limit are returned.
+func (h *ChatMessageHistory) Messages(ctx context.Context) ([]llms.ChatMessage, error) {
+	start := int64(0)
+	if h.limit > 0 {
+		start = -h.limit
+	}
+	values, err := h.client.Do(ctx, h.client.B().Lrange().Key(h.key()).Start(start).Stop(-1).Build()).AsStrSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]llms.ChatMessage, 0, len(values))
+	for _, value := range values {
+		m := llms.ChatMessageModel{}
+		if err := json.Unmarshal([]byte(value), &m); err != nil {
+			return nil, err
+		}
+		// Messages of unknown types, logged by ToChatMessage, are skipped.
+		if message := m.ToChatMessage(); message != nil {
+			messages = append(messages, message)
+		}
+	}
+
+	return messages, nil
+}
+
+// AddAIMessage adds an AIMessage to the chat message history.
+func (h *ChatMessageHistory) AddAIMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.AIChatMessage{Content: text})
+}
+
+// AddUserMessage adds a user to the chat message history.
+func (h *ChatMessageHistory) AddUserMessage(ctx context.Context, text string) error {
+	return h.AddMessage(ctx, llms.HumanChatMessage{Content: text})
+}
+
+// AddMessage adds a message to the chat message history and refreshes the TTL
+// of the session.
+func (h *ChatMessageHistory) AddMessage(ctx context.Context, message llms.ChatMessage) error {
+	data, err := json.Marshal(llms.ConvertChatMessageToModel(message))
+	if err != nil {
+		return err
+	}
+
+	cmds := rueidis.Commands{
+		h.client.B().Rpush().Key(h.key()).Element(string(data)).Build(),
+	}
+	return h.do(ctx, h.withExpire(cmds))
+}
+
+// Clear removes all messages of the session.
+func (h *ChatMessageHistory) Clear(ctx context.Context) error {
+	return h.client.Do(ctx, h.client.B().Del().Key(h.key()).Build()).Error()
+}
+
+// SetMessages atomically replaces the messages of the session.
+func (h *ChatMessageHistory) SetMessages(ctx context.Context, messages []llms.ChatMessage) error {
+	cmds := rueidis.Commands{
+		h.client.B().Multi().Build(),
+		h.client.B().Del().Key(h.key()).Build(),
+	}
+	if len(messages) > 0 {
+		elements := make([]string, 0, len(messages))
+		for _, message := range messages {
+			data, err := json.Marshal(llms.ConvertChatMessageToModel(message))
+			if err != nil {
+				return err
+			}
+			elements = append(elements, string(data))
+		}
+		cmds = h.withExpire(append(cmds, h.client.B().Rpush().Key(h.key()).Element(elements...).Build()))
+	}
+	cmds = append(cmds, h.client.B().Exec().Build())
+
+	return h.do(ctx, cmds)
+}
+
+// Close closes the Redis client if it was created by the history. Clients
+// given with WithClient are left open for their owner to close.
+func (h *ChatMessageHistory) Close() {
+	if h.ownsClient {
+		h.client.Close()
+	}
+}
+
+func (h *ChatMessageHistory) key() string {
+	return h.keyPrefix + h.sessionID
+}
+
+func (h *ChatMessageHistory) withExpire(cmds rueidis.Commands) rueidis.Commands {
+	if h.ttl <= 0 {
+		return cmds
+	}
+	return append(cmds, h.client.B().Expire().Key(h.key()).Seconds(h.ttl).Build())
+}
+
+func (h *ChatMessageHistory) do(ctx context.Context, cmds rueidis.Commands) error {
+	for _, resp := range h.client.DoMulti(ctx, cmds...) {
+		if err := resp.Error(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
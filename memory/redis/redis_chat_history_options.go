@@ -0,0 +1,89 @@
+package redis
+
+import (
+	"errors"
+	"time"
+
+	"github.com/redis/rueidis"
+)
+
+const (
+	// DefaultKeyPrefix is the default prefix of the keys holding the messages.
+	DefaultKeyPrefix = "message_store:"
+)
+
+var (
+	errInvalidConnection = errors.New("invalid redis connection option")
+	errInvalidSessionID  = errors.New("invalid redis session id option")
+)
+
+// ChatMessageHistoryOption is a function for creating new chat message history
+// with other than the default values.
+type ChatMessageHistoryOption func(m *ChatMessageHistory)
+
+// WithConnectionURL is an option for specifying the Redis connection URL.
+// Either this or WithClient must be used.
+func WithConnectionURL(connectionURL string) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.url = connectionURL
+	}
+}
+
+// WithClient is an option for specifying the Redis client.
+func WithClient(client rueidis.Client) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.client = client
+	}
+}
+
+// WithSessionID is an arbitrary key that is used to store the messages of a single chat session,
+// like user name, email, chat id etc. Must be set.
+func WithSessionID(sessionID string) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.sessionID = sessionID
+	}
+}
+
+// WithKeyPrefix is an option for specifying the prefix of the key holding the
+// messages of the session.
+func WithKeyPrefix(prefix string) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.keyPrefix = prefix
+	}
+}
+
+// WithTTL is an option for expiring the messages of a session after it has
+// been inactive for the given duration. The TTL is refreshed whenever a message
+// is added. It is rounded up to whole seconds.
+func WithTTL(ttl time.Duration) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.ttl = int64((ttl + time.Second - 1) / time.Second)
+	}
+}
+
+// WithLimit is an option for only returning the most recent messages of the
+// session. All messages are kept in Redis.
+func WithLimit(limit int) ChatMessageHistoryOption {
+	return func(h *ChatMessageHistory) {
+		h.limit = int64(limit)
+	}
+}
+
+func applyChatOptions(options ...ChatMessageHistoryOption) (*ChatMessageHistory, error) {
+	h := &ChatMessageHistory{
+		keyPrefix: DefaultKeyPrefix,
+	}
+
+	for _, option := range options {
+		option(h)
+	}
+
+	if h.client == nil && h.url == "" {
+		return nil, errInvalidConnection
+	}
+	if h.sessionID == "" {
+		return nil, errInvalidSessionID
+	}
+
+	return h, nil
+}
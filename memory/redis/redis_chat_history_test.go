@@ -0,0 +1,126 @@
+package redis
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/redis/rueidis"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func getRedisURL(t *testing.T) string {
+	t.Helper()
+
+	if url := os.Getenv("REDIS_URL"); url != "" {
+		return url
+	}
+
+	ctx := context.Background()
+	container, err := tcredis.RunContainer(ctx, testcontainers.WithImage("docker.io/redis:7.2"))
+	if err != nil && strings.Contains(err.Error(), "Cannot connect to the Docker daemon") {
+		t.Skip("Docker not available")
+	}
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		require.NoError(t, container.Terminate(context.Background()))
+	})
+
+	url, err := container.ConnectionString(ctx)
+	require.NoError(t, err)
+	return url
+}
+
+func TestRedisChatMessageHistory(t *testing.T) {
+	t.Parallel()
+
+	url := getRedisURL(t)
+	ctx := context.Background()
+
+	_, err := NewRedisChatMessageHistory(WithSessionID("test"))
+	assert.Equal(t, errInvalidConnection, err)
+
+	_, err = NewRedisChatMessageHistory(WithConnectionURL(url))
+	assert.Equal(t, errInvalidSessionID, err)
+
+	history, err := NewRedisChatMessageHistory(
+		WithConnectionURL(url),
+		WithSessionID("test-session"),
+		WithTTL(time.Hour),
+	)
+	require.NoError(t, err)
+	defer history.Close()
+
+	toolCall := llms.AIChatMessage{ToolCalls: []llms.ToolCall{{
+		ID:           "call_1",
+		Type:         "function",
+		FunctionCall: &llms.FunctionCall{Name: "weather", Arguments: `{"city":"Paris"}`},
+	}}}
+	require.NoError(t, history.AddUserMessage(ctx, "What's the weather in Paris?"))
+	require.NoError(t, history.AddMessage(ctx, toolCall))
+	require.NoError(t, history.AddMessage(ctx, llms.ToolChatMessage{ID: "call_1", Content: "Sunny"}))
+	require.NoError(t, history.AddAIMessage(ctx, "It is sunny."))
+	// Messages of unknown types are skipped.
+	require.NoError(t, history.client.Do(ctx, history.client.B().Rpush().Key(history.key()).
+		Element(`{"type":"unknown","data":{"content":"?"}}`).Build()).Error())
+
+	messages, err := history.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "What's the weather in Paris?"},
+		toolCall,
+		llms.ToolChatMessage{ID: "call_1", Content: "Sunny"},
+		llms.AIChatMessage{Content: "It is sunny."},
+	}, messages)
+
+	ttl, err := history.client.Do(ctx, history.client.B().Ttl().Key(history.key()).Build()).AsInt64()
+	require.NoError(t, err)
+	assert.Greater(t, ttl, int64(0))
+
+	windowed, err := NewRedisChatMessageHistory(WithConnectionURL(url), WithSessionID("test-session"), WithLimit(2))
+	require.NoError(t, err)
+	defer windowed.Close()
+	messages, err = windowed.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{llms.AIChatMessage{Content: "It is sunny."}}, messages)
+
+	require.NoError(t, history.SetMessages(ctx, []llms.ChatMessage{llms.SystemChatMessage{Content: "Be brief."}}))
+	messages, err = history.Messages(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{llms.SystemChatMessage{Content: "Be brief."}}, messages)
+
+	require.NoError(t, history.Clear(ctx))
+	messages, err = history.Messages(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, messages)
+}
+
+// closeRecordingClient is a Redis client recording whether it was closed.
+type closeRecordingClient struct {
+	rueidis.Client
+
+	closed bool
+}
+
+func (c *closeRecordingClient) Close() {
+	c.closed = true
+}
+
+func TestRedisChatMessageHistoryOptions(t *testing.T) {
+	t.Parallel()
+
+	client := &closeRecordingClient{}
+	history, err := NewRedisChatMessageHistory(WithClient(client), WithSessionID("test"), WithTTL(500*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), history.ttl)
+
+	// The injected client is owned by the caller.
+	history.Close()
+	assert.False(t, client.closed)
+}
@@ -0,0 +1,208 @@
+package sqlite3
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	_ "github.com/mattn/go-sqlite3" // sqlite3 driver.
+	"github.com/tmc/langchaingo/memory"
+)
+
+// DefaultEntityTableName sets a default table name for the entity store.
+const DefaultEntityTableName = "langchaingo_entities"
+
+// DefaultEntitySchema sets a default schema for the entity store to be run after connecting.
+const DefaultEntitySchema = `CREATE TABLE IF NOT EXISTS %s (
+		session TEXT NOT NULL,
+		entity TEXT NOT NULL,
+		summary TEXT NOT NULL,
+		updated TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (session, entity)
+);`
+
+// SqliteEntityStore is an entity store for the entity memory backed by sqlite3.
+type SqliteEntityStore struct {
+	// DB is the database connection.
+	DB *sql.DB
+	// Ctx is a context that can be used for the schema exec.
+	//nolint:containedctx // This is used only when execing schema.
+	Ctx context.Context
+	// DBAddress is the address or file path for connecting the db.
+	DBAddress string
+	// TableName is the name of the entities table.
+	TableName string
+	// Session defines a session name or id for a conversation.
+	Session string
+	// Schema defines a initial schema to be run.
+	Schema []byte
+}
+
+// Statically assert that SqliteEntityStore implement the entity store interface.
+var _ memory.EntityStore = &SqliteEntityStore{}
+
+// NewSqliteEntityStore creates a new SqliteEntityStore using entity store options.
+func NewSqliteEntityStore(options ...SqliteEntityStoreOption) *SqliteEntityStore {
+	return applyEntityStoreOptions(options...)
+}
+
+// Get returns the summary of an entity, or an empty string if it is not stored.
+func (s *SqliteEntityStore) Get(ctx context.Context, entity string) (string, error) {
+	querytpl := []string{
+		"SELECT summary FROM ",
+		" WHERE session = ? AND entity = ?;",
+	}
+	query := strings.Join(querytpl, s.TableName)
+
+	var summary string
+	err := s.DB.QueryRowContext(ctx, query, s.Session, entity).Scan(&summary)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return summary, err
+}
+
+// Set stores the summary of an entity.
+func (s *SqliteEntityStore) Set(ctx context.Context, entity string, summary string) error {
+	querytpl := []string{
+		"INSERT INTO ",
+		" (session, entity, summary) VALUES (?, ?, ?)" +
+			" ON CONFLICT (session, entity) DO UPDATE SET summary = excluded.summary, updated = CURRENT_TIMESTAMP;",
+	}
+	query := strings.Join(querytpl, s.TableName)
+	_, err := s.DB.ExecContext(ctx, query, s.Session, entity, summary)
+	return err
+}
+
+// Delete removes an entity from the store.
+func (s *SqliteEntityStore) Delete(ctx context.Context, entity string) error {
+	querytpl := []string{
+		"DELETE FROM ",
+		" WHERE session = ? AND entity = ?;",
+	}
+	query := strings.Join(querytpl, s.TableName)
+	_, err := s.DB.ExecContext(ctx, query, s.Session, entity)
+	return err
+}
+
+// Exists reports whether an entity is in the store.
+func (s *SqliteEntityStore) Exists(ctx context.Context, entity string) (bool, error) {
+	querytpl := []string{
+		"SELECT COUNT(*) FROM ",
+		" WHERE session = ? AND entity = ?;",
+	}
+	query := strings.Join(querytpl, s.TableName)
+
+	var count int
+	if err := s.DB.QueryRowContext(ctx, query, s.Session, entity).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// Clear removes all entities of the session from the store.
+func (s *SqliteEntityStore) Clear(ctx context.Context) error {
+	querytpl := []string{
+		"DELETE FROM ",
+		" WHERE session = ?;",
+	}
+	query := strings.Join(querytpl, s.TableName)
+	_, err := s.DB.ExecContext(ctx, query, s.Session)
+	return err
+}
+
+// SqliteEntityStoreOption is a function for creating new
+// entity store with other than the default values.
+type SqliteEntityStoreOption func(s *SqliteEntityStore)
+
+// WithEntityDB is an option for NewSqliteEntityStore for adding
+// a database connection.
+func WithEntityDB(db *sql.DB) SqliteEntityStoreOption {
+	return func(s *SqliteEntityStore) {
+		s.DB = db
+	}
+}
+
+// WithEntityContext is an option for NewSqliteEntityStore
+// to use a context internally when running Schema.
+func WithEntityContext(ctx context.Context) SqliteEntityStoreOption {
+	return func(s *SqliteEntityStore) {
+		s.Ctx = ctx //nolint:fatcontext
+	}
+}
+
+// WithEntityDBAddress is an option for NewSqliteEntityStore for
+// specifying an address or file path for when connecting the db.
+func WithEntityDBAddress(addr string) SqliteEntityStoreOption {
+	return func(s *SqliteEntityStore) {
+		s.DBAddress = addr
+	}
+}
+
+// WithEntityTableName is an option for NewSqliteEntityStore for
+// specifying the name of the entities table.
+func WithEntityTableName(name string) SqliteEntityStoreOption {
+	return func(s *SqliteEntityStore) {
+		s.TableName = name
+	}
+}
+
+// WithEntitySession is an option for NewSqliteEntityStore for
+// setting a session name or id for the entities.
+func WithEntitySession(session string) SqliteEntityStoreOption {
+	return func(s *SqliteEntityStore) {
+		s.Session = session
+	}
+}
+
+// WithEntitySchema is an option for NewSqliteEntityStore for
+// running a schema when connected. Useful for migrations for example.
+func WithEntitySchema(schema []byte) SqliteEntityStoreOption {
+	return func(s *SqliteEntityStore) {
+		s.Schema = schema
+	}
+}
+
+func applyEntityStoreOptions(options ...SqliteEntityStoreOption) *SqliteEntityStore {
+	s := &SqliteEntityStore{}
+
+	for _, option := range options {
+		option(s)
+	}
+
+	if s.TableName == "" {
+		s.TableName = DefaultEntityTableName
+	}
+
+	if s.Schema == nil {
+		s.Schema = []byte(fmt.Sprintf(DefaultEntitySchema, s.TableName))
+	}
+
+	if s.Ctx == nil {
+		s.Ctx = context.Background()
+	}
+
+	if s.DBAddress == "" {
+		s.DBAddress = ":memory:"
+	}
+
+	if s.Session == "" {
+		s.Session = "default"
+	}
+
+	if s.DB == nil {
+		db, err := sql.Open("sqlite3", s.DBAddress)
+		if err != nil {
+			panic(err)
+		}
+		s.DB = db
+	}
+
+	if _, err := s.DB.ExecContext(s.Ctx, string(s.Schema)); err != nil {
+		panic(err)
+	}
+
+	return s
+}
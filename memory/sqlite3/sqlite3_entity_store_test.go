@@ -0,0 +1,43 @@
+package sqlite3_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/memory/sqlite3"
+)
+
+func TestSqliteEntityStore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	s := sqlite3.NewSqliteEntityStore(sqlite3.WithEntityContext(ctx))
+
+	summary, err := s.Get(ctx, "Paris")
+	require.NoError(t, err)
+	assert.Equal(t, "", summary)
+
+	require.NoError(t, s.Set(ctx, "Paris", "The capital of France."))
+	require.NoError(t, s.Set(ctx, "Paris", "The capital of France, where Anna lives."))
+
+	summary, err = s.Get(ctx, "Paris")
+	require.NoError(t, err)
+	assert.Equal(t, "The capital of France, where Anna lives.", summary)
+
+	exists, err := s.Exists(ctx, "Paris")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	require.NoError(t, s.Delete(ctx, "Paris"))
+	exists, err = s.Exists(ctx, "Paris")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	require.NoError(t, s.Set(ctx, "Anna", "A friend."))
+	require.NoError(t, s.Clear(ctx))
+	exists, err = s.Exists(ctx, "Anna")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	"strings"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const _defaultSummaryTemplate = `Progressively summarize the lines of conversation provided, adding onto the previous summary returning a new summary.
+
+EXAMPLE
+Current summary:
+The human asks what the AI thinks of artificial intelligence. The AI thinks artificial intelligence is a force for good.
+
+New lines of conversation:
+Human: Why do you think artificial intelligence is a force for good?
+AI: Because artificial intelligence will help humans reach their full potential.
+
+New summary:
+The human asks what the AI thinks of artificial intelligence. The AI thinks artificial intelligence is a force for good because it will help humans reach their full potential.
+END OF EXAMPLE
+
+Current summary:
+{{.summary}}
+
+New lines of conversation:
+{{.new_lines}}
+
+New summary:`
+
+// ConversationSummary is a memory that uses an LLM to progressively summarize the
+// conversation. Instead of the raw messages, the chat history only stores the
+// running summary, as a system message at the start of the history.
+type ConversationSummary struct {
+	ConversationBuffer
+	LLM           llms.Model
+	SummaryPrompt prompts.PromptTemplate
+}
+
+// Statically assert that ConversationSummary implement the memory interface.
+var _ schema.Memory = &ConversationSummary{}
+
+// NewConversationSummary is a function for creating a new summary memory.
+func NewConversationSummary(llm llms.Model, options ...ConversationBufferOption) *ConversationSummary {
+	return &ConversationSummary{
+		ConversationBuffer: *applyBufferOptions(options...),
+		LLM:                llm,
+		SummaryPrompt:      prompts.NewPromptTemplate(_defaultSummaryTemplate, []string{"summary", "new_lines"}),
+	}
+}
+
+// MemoryVariables uses ConversationBuffer method for memory variables.
+func (s *ConversationSummary) MemoryVariables(ctx context.Context) []string {
+	return s.ConversationBuffer.MemoryVariables(ctx)
+}
+
+// LoadMemoryVariables returns the current summary of the conversation. If
+// ReturnMessages is set to true the summary is returned as a slice containing a
+// single system message.
+func (s *ConversationSummary) LoadMemoryVariables(ctx context.Context, _ map[string]any) (map[string]any, error) {
+	messages, err := s.ChatHistory.Messages(ctx)
+	if err != nil {
+		return nil, err
+	}
+	summary, _ := splitSummary(messages)
+
+	if s.ReturnMessages {
+		return map[string]any{
+			s.MemoryKey: summaryMessages(summary),
+		}, nil
+	}
+
+	return map[string]any{
+		s.MemoryKey: summary,
+	}, nil
+}
+
+// SaveContext folds the new user and AI messages into the running summary.
+func (s *ConversationSummary) SaveContext(
+	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
+) error {
+	err := s.ConversationBuffer.SaveContext(ctx, inputValues, outputValues)
+	if err != nil {
+		return err
+	}
+	messages, err := s.ChatHistory.Messages(ctx)
+	if err != nil {
+		return err
+	}
+
+	summary, newMessages := splitSummary(messages)
+	summary, err = s.predictNewSummary(ctx, summary, newMessages)
+	if err != nil {
+		return err
+	}
+
+	return s.ChatHistory.SetMessages(ctx, summaryMessages(summary))
+}
+
+// Clear uses ConversationBuffer method for clearing buffer memory.
+func (s *ConversationSummary) Clear(ctx context.Context) error {
+	return s.ConversationBuffer.Clear(ctx)
+}
+
+// predictNewSummary asks the LLM to extend the summary with the given messages.
+func (s *ConversationSummary) predictNewSummary(
+	ctx context.Context, summary string, messages []llms.ChatMessage,
+) (string, error) {
+	if len(messages) == 0 {
+		return summary, nil
+	}
+
+	newLines, err := llms.GetBufferString(messages, s.HumanPrefix, s.AIPrefix)
+	if err != nil {
+		return "", err
+	}
+	prompt, err := s.SummaryPrompt.Format(map[string]any{
+		"summary":   summary,
+		"new_lines": newLines,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	newSummary, err := llms.GenerateFromSinglePrompt(ctx, s.LLM, prompt)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(newSummary), nil
+}
+
+// splitSummary returns the summary stored as the leading system message of the
+// messages, together with the messages that follow it.
+func splitSummary(messages []llms.ChatMessage) (string, []llms.ChatMessage) {
+	if len(messages) > 0 {
+		if m, ok := messages[0].(llms.SystemChatMessage); ok {
+			return m.Content, messages[1:]
+		}
+	}
+	return "", messages
+}
+
+func summaryMessages(summary string) []llms.ChatMessage {
+	if summary == "" {
+		return []llms.ChatMessage{}
+	}
+	return []llms.ChatMessage{llms.SystemChatMessage{Content: summary}}
+}
@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// ConversationSummaryBuffer is a memory that keeps the most recent messages
+// verbatim as long as they fit in MaxTokenLimit. Older messages are evicted from
+// the buffer and folded by an LLM into a running summary, which is stored as a
+// system message at the start of the chat history.
+type ConversationSummaryBuffer struct {
+	ConversationSummary
+	MaxTokenLimit int
+}
+
+// Statically assert that ConversationSummaryBuffer implement the memory interface.
+var _ schema.Memory = &ConversationSummaryBuffer{}
+
+// NewConversationSummaryBuffer is a function for creating a new summary buffer memory.
+func NewConversationSummaryBuffer(
+	llm llms.Model,
+	maxTokenLimit int,
+	options ...ConversationBufferOption,
+) *ConversationSummaryBuffer {
+	return &ConversationSummaryBuffer{
+		ConversationSummary: *NewConversationSummary(llm, options...),
+		MaxTokenLimit:       maxTokenLimit,
+	}
+}
+
+// MemoryVariables uses ConversationBuffer method for memory variables.
+func (sb *ConversationSummaryBuffer) MemoryVariables(ctx context.Context) []string {
+	return sb.ConversationBuffer.MemoryVariables(ctx)
+}
+
+// LoadMemoryVariables returns the summary of the evicted messages, as a system
+// message, followed by the messages still in the buffer.
+func (sb *ConversationSummaryBuffer) LoadMemoryVariables(
+	ctx context.Context, inputs map[string]any,
+) (map[string]any, error) {
+	return sb.ConversationBuffer.LoadMemoryVariables(ctx, inputs)
+}
+
+// SaveContext uses ConversationBuffer method for saving context, then evicts the
+// oldest messages into the summary while the buffer exceeds MaxTokenLimit.
+func (sb *ConversationSummaryBuffer) SaveContext(
+	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
+) error {
+	err := sb.ConversationBuffer.SaveContext(ctx, inputValues, outputValues)
+	if err != nil {
+		return err
+	}
+	messages, err := sb.ChatHistory.Messages(ctx)
+	if err != nil {
+		return err
+	}
+
+	summary, buffer := splitSummary(messages)
+	evicted := 0
+	for evicted < len(buffer) {
+		numTokens, err := sb.getNumTokens(buffer[evicted:])
+		if err != nil {
+			return err
+		}
+		if numTokens <= sb.MaxTokenLimit {
+			break
+		}
+		evicted++
+	}
+	if evicted == 0 {
+		return nil
+	}
+
+	summary, err = sb.predictNewSummary(ctx, summary, buffer[:evicted])
+	if err != nil {
+		return err
+	}
+
+	return sb.ChatHistory.SetMessages(ctx, append(summaryMessages(summary), buffer[evicted:]...))
+}
+
+// Clear uses ConversationBuffer method for clearing buffer memory.
+func (sb *ConversationSummaryBuffer) Clear(ctx context.Context) error {
+	return sb.ConversationBuffer.Clear(ctx)
+}
+
+func (sb *ConversationSummaryBuffer) getNumTokens(messages []llms.ChatMessage) (int, error) {
+	bufferString, err := llms.GetBufferString(messages, sb.HumanPrefix, sb.AIPrefix)
+	if err != nil {
+		return 0, err
+	}
+
+	return sb.ConversationBuffer.numTokens(bufferString), nil
+}
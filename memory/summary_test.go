@@ -0,0 +1,92 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/fake"
+)
+
+func TestConversationSummary(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.NewFakeLLM([]string{
+		"The human greets the AI.",
+		"The human greets the AI and asks for its name.",
+	})
+	m := NewConversationSummary(llm)
+
+	result, err := m.LoadMemoryVariables(context.Background(), map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": ""}, result)
+
+	err = m.SaveContext(context.Background(), map[string]any{"input": "hi"}, map[string]any{"output": "hello"})
+	require.NoError(t, err)
+	err = m.SaveContext(context.Background(), map[string]any{"input": "name?"}, map[string]any{"output": "bot"})
+	require.NoError(t, err)
+
+	result, err = m.LoadMemoryVariables(context.Background(), map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "The human greets the AI and asks for its name."}, result)
+
+	messages, err := m.ChatHistory.Messages(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "The human greets the AI and asks for its name."},
+	}, messages)
+}
+
+func TestConversationSummaryReturnMessages(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.NewFakeLLM([]string{"The human greets the AI."})
+	m := NewConversationSummary(llm, WithReturnMessages(true))
+
+	result, err := m.LoadMemoryVariables(context.Background(), map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []llms.ChatMessage{}}, result)
+
+	err = m.SaveContext(context.Background(), map[string]any{"input": "hi"}, map[string]any{"output": "hello"})
+	require.NoError(t, err)
+
+	result, err = m.LoadMemoryVariables(context.Background(), map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "The human greets the AI."},
+	}}, result)
+}
+
+func TestConversationSummaryBuffer(t *testing.T) {
+	t.Parallel()
+
+	llm := fake.NewFakeLLM([]string{"The human said foo."})
+	// Count words, as the number of tokens counted by llms.CountTokens depends
+	// on whether the tiktoken encoding can be loaded.
+	m := NewConversationSummaryBuffer(llm, 7, WithReturnMessages(true),
+		WithTokenCounter(func(text string) int { return len(strings.Fields(text)) }))
+
+	err := m.SaveContext(context.Background(), map[string]any{"input": "foo"}, map[string]any{"output": "bar"})
+	require.NoError(t, err)
+
+	result, err := m.LoadMemoryVariables(context.Background(), map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []llms.ChatMessage{
+		llms.HumanChatMessage{Content: "foo"},
+		llms.AIChatMessage{Content: "bar"},
+	}}, result)
+
+	err = m.SaveContext(context.Background(), map[string]any{"input": "a longer question"}, map[string]any{"output": "ok"})
+	require.NoError(t, err)
+
+	result, err = m.LoadMemoryVariables(context.Background(), map[string]any{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": []llms.ChatMessage{
+		llms.SystemChatMessage{Content: "The human said foo."},
+		llms.HumanChatMessage{Content: "a longer question"},
+		llms.AIChatMessage{Content: "ok"},
+	}}, result)
+}
@@ -105,5 +105,5 @@ func (tb *ConversationTokenBuffer) getNumTokensFromMessages(ctx context.Context)
 		return 0, err
 	}
 
-	return llms.CountTokens("", bufferString), nil
+	return tb.ConversationBuffer.numTokens(bufferString), nil
 }
@@ -0,0 +1,250 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const (
+	// _vectorStoreTimestampKey is the metadata key holding the time an exchange was saved.
+	_vectorStoreTimestampKey = "timestamp"
+	// _vectorStoreSessionKey is the metadata key holding the session an exchange belongs to.
+	_vectorStoreSessionKey = "session_id"
+	// _vectorStoreMaxOverFetch bounds the factor by which the number of
+	// candidates is increased when the documents of other sessions are
+	// returned by vector stores that ignore namespaces.
+	_vectorStoreMaxOverFetch = 16
+)
+
+// VectorStoreRetriever is a memory that writes every saved exchange to a vector
+// store and, when loading memory variables, retrieves the past exchanges that
+// are the most relevant to the current input instead of the most recent ones.
+type VectorStoreRetriever struct {
+	VectorStore vectorstores.VectorStore
+
+	// NumDocuments is the number of past exchanges returned on load.
+	NumDocuments int
+	// SessionID, if set, is used as the vector store namespace and stored in the
+	// metadata of every exchange, so that sessions do not see each other.
+	SessionID string
+	// RecencyDecayRate enables recency weighting when greater than zero. The
+	// score of each retrieved exchange is increased by (1-RecencyDecayRate)^h,
+	// where h is the number of hours since the exchange was saved.
+	RecencyDecayRate float64
+	// FetchK is the number of candidates fetched from the vector store before
+	// recency weighting picks the best NumDocuments.
+	FetchK int
+	// VectorStoreOptions are passed to every call made to the vector store.
+	VectorStoreOptions []vectorstores.Option
+
+	ReturnDocs bool
+	InputKey   string
+	MemoryKey  string
+
+	// now returns the current time. It is overridden in tests, and time.Now is
+	// used if it is nil.
+	now func() time.Time
+}
+
+// Statically assert that VectorStoreRetriever implement the memory interface.
+var _ schema.Memory = &VectorStoreRetriever{}
+
+// NewVectorStoreRetriever creates a new vector store backed memory.
+func NewVectorStoreRetriever(
+	store vectorstores.VectorStore,
+	options ...VectorStoreRetrieverOption,
+) *VectorStoreRetriever {
+	return applyVectorStoreRetrieverOptions(store, options...)
+}
+
+// GetMemoryKey getter for memory key.
+func (m *VectorStoreRetriever) GetMemoryKey(context.Context) string {
+	return m.MemoryKey
+}
+
+// MemoryVariables gets the input key the vector store memory will load dynamically.
+func (m *VectorStoreRetriever) MemoryVariables(context.Context) []string {
+	return []string{m.MemoryKey}
+}
+
+// LoadMemoryVariables searches the vector store for the past exchanges relevant
+// to the input. If ReturnDocs is set to true the output is a slice of
+// schema.Document, otherwise the contents of the documents joined by newlines.
+func (m *VectorStoreRetriever) LoadMemoryVariables(
+	ctx context.Context, inputs map[string]any,
+) (map[string]any, error) {
+	query, err := GetInputValue(m.filterInputs(inputs), m.InputKey)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := m.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	docs = m.rankDocuments(docs)
+
+	if m.ReturnDocs {
+		return map[string]any{
+			m.MemoryKey: docs,
+		}, nil
+	}
+
+	contents := make([]string, 0, len(docs))
+	for _, doc := range docs {
+		contents = append(contents, doc.PageContent)
+	}
+	return map[string]any{
+		m.MemoryKey: strings.Join(contents, "\n"),
+	}, nil
+}
+
+// SaveContext saves the input and output values as a single document in the
+// vector store. Every key and value is written on its own line.
+func (m *VectorStoreRetriever) SaveContext(
+	ctx context.Context, inputValues map[string]any, outputValues map[string]any,
+) error {
+	lines := append(formatValues(m.filterInputs(inputValues)), formatValues(outputValues)...)
+
+	metadata := map[string]any{
+		_vectorStoreTimestampKey: m.currentTime().Unix(),
+	}
+	if m.SessionID != "" {
+		metadata[_vectorStoreSessionKey] = m.SessionID
+	}
+
+	_, err := m.VectorStore.AddDocuments(ctx, []schema.Document{{
+		PageContent: strings.Join(lines, "\n"),
+		Metadata:    metadata,
+	}}, m.vectorStoreOptions()...)
+	return err
+}
+
+// Clear does nothing: documents are not removed from the vector store, as the
+// VectorStore interface has no way to delete them.
+func (m *VectorStoreRetriever) Clear(context.Context) error {
+	return nil
+}
+
+func (m *VectorStoreRetriever) vectorStoreOptions() []vectorstores.Option {
+	if m.SessionID == "" {
+		return m.VectorStoreOptions
+	}
+	return append([]vectorstores.Option{vectorstores.WithNameSpace(m.SessionID)}, m.VectorStoreOptions...)
+}
+
+// filterInputs removes the memory key from the inputs, as it holds the memory
+// variables loaded previously and not user input.
+func (m *VectorStoreRetriever) filterInputs(inputs map[string]any) map[string]any {
+	filtered := make(map[string]any, len(inputs))
+	for k, v := range inputs {
+		if k != m.MemoryKey {
+			filtered[k] = v
+		}
+	}
+	return filtered
+}
+
+// search returns the candidate documents of the session for the query. Vector
+// stores that ignore the namespace of the session also return the documents of
+// other sessions, which are dropped: more candidates are then fetched, up to
+// _vectorStoreMaxOverFetch times as many, until enough are left.
+func (m *VectorStoreRetriever) search(ctx context.Context, query string) ([]schema.Document, error) {
+	fetchK := m.NumDocuments
+	if m.RecencyDecayRate > 0 && m.FetchK > fetchK {
+		fetchK = m.FetchK
+	}
+
+	for k := fetchK; ; k *= 2 {
+		docs, err := m.VectorStore.SimilaritySearch(ctx, query, k, m.vectorStoreOptions()...)
+		if err != nil {
+			return nil, err
+		}
+		sessionDocs := m.sessionDocuments(docs)
+		if len(sessionDocs) >= fetchK || len(docs) < k || k >= fetchK*_vectorStoreMaxOverFetch {
+			return sessionDocs, nil
+		}
+	}
+}
+
+// sessionDocuments drops the documents of other sessions.
+func (m *VectorStoreRetriever) sessionDocuments(docs []schema.Document) []schema.Document {
+	if m.SessionID == "" {
+		return docs
+	}
+	filtered := make([]schema.Document, 0, len(docs))
+	for _, doc := range docs {
+		if session, ok := doc.Metadata[_vectorStoreSessionKey]; ok && session != m.SessionID {
+			continue
+		}
+		filtered = append(filtered, doc)
+	}
+	return filtered
+}
+
+// rankDocuments sorts the documents by their recency weighted score, if recency
+// weighting is enabled. At most NumDocuments documents are returned.
+func (m *VectorStoreRetriever) rankDocuments(docs []schema.Document) []schema.Document {
+	ranked := docs
+	if m.RecencyDecayRate > 0 {
+		now := m.currentTime()
+		weight := func(doc schema.Document) float64 {
+			saved := time.Unix(metadataUnixTime(doc.Metadata[_vectorStoreTimestampKey]), 0)
+			hours := math.Max(now.Sub(saved).Hours(), 0)
+			return float64(doc.Score) + math.Pow(1-m.RecencyDecayRate, hours)
+		}
+		sort.SliceStable(ranked, func(i, j int) bool { return weight(ranked[i]) > weight(ranked[j]) })
+	}
+
+	if len(ranked) > m.NumDocuments {
+		ranked = ranked[:m.NumDocuments]
+	}
+	return ranked
+}
+
+func (m *VectorStoreRetriever) currentTime() time.Time {
+	if m.now == nil {
+		return time.Now()
+	}
+	return m.now()
+}
+
+// metadataUnixTime converts a timestamp read back from a vector store, which
+// may have been decoded as any numeric type, to unix seconds.
+func metadataUnixTime(v any) int64 {
+	switch t := v.(type) {
+	case int64:
+		return t
+	case int:
+		return int64(t)
+	case int32:
+		return int64(t)
+	case float64:
+		return int64(t)
+	case float32:
+		return int64(t)
+	default:
+		return 0
+	}
+}
+
+func formatValues(values map[string]any) []string {
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, 0, len(keys))
+	for _, k := range keys {
+		lines = append(lines, fmt.Sprintf("%s: %v", k, values[k]))
+	}
+	return lines
+}
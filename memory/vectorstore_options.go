@@ -0,0 +1,94 @@
+package memory
+
+import (
+	"time"
+
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+const (
+	// defaultVectorStoreNumDocuments is the default number of past exchanges retrieved.
+	defaultVectorStoreNumDocuments = 4
+	// defaultVectorStoreFetchK is the default number of candidates fetched for recency weighting.
+	defaultVectorStoreFetchK = 20
+)
+
+// VectorStoreRetrieverOption is a function for creating a new vector store
+// memory with other than the default values.
+type VectorStoreRetrieverOption func(m *VectorStoreRetriever)
+
+// WithNumDocuments is an option for specifying the number of past exchanges
+// returned when loading memory variables.
+func WithNumDocuments(numDocuments int) VectorStoreRetrieverOption {
+	return func(m *VectorStoreRetriever) {
+		m.NumDocuments = numDocuments
+	}
+}
+
+// WithSessionID is an option for specifying the session the memory belongs to.
+// The session ID is used as the vector store namespace.
+func WithSessionID(sessionID string) VectorStoreRetrieverOption {
+	return func(m *VectorStoreRetriever) {
+		m.SessionID = sessionID
+	}
+}
+
+// WithRecencyDecayRate is an option for weighting retrieved exchanges by how
+// recently they were saved. fetchK candidates are retrieved from the vector
+// store and re-ranked by their similarity score plus (1-decayRate)^hours.
+func WithRecencyDecayRate(decayRate float64, fetchK int) VectorStoreRetrieverOption {
+	return func(m *VectorStoreRetriever) {
+		m.RecencyDecayRate = decayRate
+		m.FetchK = fetchK
+	}
+}
+
+// WithVectorStoreOptions is an option for specifying options passed to every
+// call made to the vector store, e.g. filters or a score threshold.
+func WithVectorStoreOptions(options ...vectorstores.Option) VectorStoreRetrieverOption {
+	return func(m *VectorStoreRetriever) {
+		m.VectorStoreOptions = options
+	}
+}
+
+// WithReturnDocs is an option for returning the retrieved documents instead of
+// their joined contents.
+func WithReturnDocs(returnDocs bool) VectorStoreRetrieverOption {
+	return func(m *VectorStoreRetriever) {
+		m.ReturnDocs = returnDocs
+	}
+}
+
+// WithVectorStoreInputKey is an option for specifying the input key used as the
+// search query.
+func WithVectorStoreInputKey(inputKey string) VectorStoreRetrieverOption {
+	return func(m *VectorStoreRetriever) {
+		m.InputKey = inputKey
+	}
+}
+
+// WithVectorStoreMemoryKey is an option for specifying the memory key.
+func WithVectorStoreMemoryKey(memoryKey string) VectorStoreRetrieverOption {
+	return func(m *VectorStoreRetriever) {
+		m.MemoryKey = memoryKey
+	}
+}
+
+func applyVectorStoreRetrieverOptions(
+	store vectorstores.VectorStore,
+	opts ...VectorStoreRetrieverOption,
+) *VectorStoreRetriever {
+	m := &VectorStoreRetriever{
+		VectorStore:  store,
+		NumDocuments: defaultVectorStoreNumDocuments,
+		FetchK:       defaultVectorStoreFetchK,
+		MemoryKey:    "history",
+		now:          time.Now,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	return m
+}
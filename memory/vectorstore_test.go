@@ -0,0 +1,136 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/vectorstores"
+)
+
+// wordStore is a vector store that scores documents by the fraction of query
+// words they contain, keeping documents of each namespace separate unless
+// ignoreNamespace is set.
+type wordStore struct {
+	docs            map[string][]schema.Document
+	ignoreNamespace bool
+}
+
+func (s *wordStore) AddDocuments(
+	_ context.Context, docs []schema.Document, options ...vectorstores.Option,
+) ([]string, error) {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if s.ignoreNamespace {
+		opts.NameSpace = ""
+	}
+	s.docs[opts.NameSpace] = append(s.docs[opts.NameSpace], docs...)
+	return nil, nil
+}
+
+func (s *wordStore) SimilaritySearch(
+	_ context.Context, query string, numDocuments int, options ...vectorstores.Option,
+) ([]schema.Document, error) {
+	opts := vectorstores.Options{}
+	for _, opt := range options {
+		opt(&opts)
+	}
+	if s.ignoreNamespace {
+		opts.NameSpace = ""
+	}
+	words := strings.Fields(strings.ToLower(query))
+	docs := make([]schema.Document, 0)
+	for _, doc := range s.docs[opts.NameSpace] {
+		matches := 0
+		for _, w := range words {
+			if strings.Contains(strings.ToLower(doc.PageContent), w) {
+				matches++
+			}
+		}
+		doc.Score = float32(matches) / float32(len(words))
+		docs = append(docs, doc)
+	}
+	sort.SliceStable(docs, func(i, j int) bool { return docs[i].Score > docs[j].Score })
+	if len(docs) > numDocuments {
+		docs = docs[:numDocuments]
+	}
+	return docs, nil
+}
+
+func TestVectorStoreRetriever(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := &wordStore{docs: map[string][]schema.Document{}}
+	m := NewVectorStoreRetriever(store, WithNumDocuments(1), WithSessionID("alice"))
+
+	err := m.SaveContext(ctx, map[string]any{"input": "my favourite food is pizza"}, map[string]any{"output": "noted"})
+	require.NoError(t, err)
+	err = m.SaveContext(ctx, map[string]any{"input": "my dog is called rex"}, map[string]any{"output": "nice"})
+	require.NoError(t, err)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{"input": "what food do I like?"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "input: my favourite food is pizza\noutput: noted"}, result)
+
+	other := NewVectorStoreRetriever(store, WithSessionID("bob"))
+	result, err = other.LoadMemoryVariables(ctx, map[string]any{"input": "what food do I like?"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": ""}, result)
+}
+
+func TestVectorStoreRetrieverRecency(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := &wordStore{docs: map[string][]schema.Document{}}
+	m := NewVectorStoreRetriever(store, WithNumDocuments(1), WithRecencyDecayRate(0.5, 10), WithReturnDocs(true))
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now.Add(-48 * time.Hour) }
+	err := m.SaveContext(ctx, map[string]any{"input": "I live in Paris"}, map[string]any{"output": "ok"})
+	require.NoError(t, err)
+	m.now = func() time.Time { return now }
+	err = m.SaveContext(ctx, map[string]any{"input": "I moved to Berlin"}, map[string]any{"output": "ok"})
+	require.NoError(t, err)
+
+	result, err := m.LoadMemoryVariables(ctx, map[string]any{"input": "where do I live?"})
+	require.NoError(t, err)
+	docs, ok := result["history"].([]schema.Document)
+	require.True(t, ok)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "input: I moved to Berlin\noutput: ok", docs[0].PageContent)
+}
+
+func TestVectorStoreRetrieverIgnoredNamespace(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := &wordStore{docs: map[string][]schema.Document{}, ignoreNamespace: true}
+	// Built as a struct literal, without the time function set by the
+	// constructor.
+	alice := &VectorStoreRetriever{
+		VectorStore: store, NumDocuments: 2, SessionID: "alice", InputKey: "input", MemoryKey: "history",
+	}
+	bob := NewVectorStoreRetriever(store, WithNumDocuments(2), WithSessionID("bob"))
+
+	require.NoError(t, alice.SaveContext(ctx, map[string]any{"input": "I like tea"}, map[string]any{"output": "ok"}))
+	require.NoError(t, alice.SaveContext(ctx, map[string]any{"input": "I like cake"}, map[string]any{"output": "ok"}))
+	for i := 0; i < 5; i++ {
+		err := bob.SaveContext(ctx, map[string]any{"input": "I like coffee"}, map[string]any{"output": "ok"})
+		require.NoError(t, err)
+	}
+
+	// Bob's exchanges are the most similar, but are fetched and dropped until
+	// enough of Alice's are found.
+	result, err := alice.LoadMemoryVariables(ctx, map[string]any{"input": "I like coffee"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{"history": "input: I like tea\noutput: ok\ninput: I like cake\noutput: ok"}, result)
+}
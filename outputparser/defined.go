@@ -2,7 +2,6 @@ package outputparser
 
 import (
 	"bytes"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -57,18 +56,11 @@ func (p Defined[T]) GetFormatInstructions() string {
 	return fmt.Sprintf(instructions, p.schema)
 }
 
-// Parse parses the output of an LLM call.
+// Parse parses the output of an LLM call. The JSON can be in a markdown code
+// block or bare, and is extracted with ExtractJSON.
 func (p Defined[T]) Parse(text string) (T, error) {
 	var target T
-
-	// Removes '```json' and '```' from the start and end of the text.
-	const opening = "```json"
-	const closing = "```"
-	if text[:len(opening)] != opening || text[len(text)-len(closing):] != closing {
-		return target, fmt.Errorf("input text should start with %s and end with %s", opening, closing)
-	}
-	parseableJSON := text[len(opening) : len(text)-len(closing)]
-	if err := json.Unmarshal([]byte(parseableJSON), &target); err != nil {
+	if err := ParseJSON(text, &target); err != nil {
 		return target, fmt.Errorf("could not parse generated JSON: %w", err)
 	}
 	return target, nil
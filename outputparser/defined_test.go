@@ -131,3 +131,38 @@ func TestDefinedParse(t *testing.T) {
 		}
 	}
 }
+
+func TestDefinedParseLenient(t *testing.T) {
+	t.Parallel()
+	type shape struct {
+		Name     string `json:"name"`
+		NumSides int    `json:"numSides"`
+	}
+	parser, err := NewDefined(shape{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		input    string
+		expected shape
+		wantErr  bool
+	}{
+		"short":        {input: "no", wantErr: true},
+		"bare":         {input: `{"name": "square", "numSides": 4}`, expected: shape{"square", 4}},
+		"prose":        {input: "Sure:\n```json\n{\"name\": \"triangle\", \"numSides\": 3,}\n```\nDone.", expected: shape{"triangle", 3}}, //nolint:lll //nolint:lll
+		"wrong fields": {input: `{"name": 3}`, wantErr: true},
+		"truncated":    {input: "```json\n{\"name\": \"pentagon\", \"numSides\": 5", wantErr: true},
+		"cut in value": {input: `{"numSides": 5, "name": "penta`, wantErr: true},
+		"unclosed":     {input: "```json\n{\"numSides\": 5, \"name\": \"pentagon\"", expected: shape{"pentagon", 5}},
+	}
+	for name, test := range tests {
+		output, err := parser.Parse(test.input)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%s: got error %v; want error %v", name, err, test.wantErr)
+		}
+		if !test.wantErr && output != test.expected {
+			t.Errorf("%s: got %+v; want %+v", name, output, test.expected)
+		}
+	}
+}
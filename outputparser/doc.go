@@ -17,5 +17,13 @@ The outputparser package includes the following parsers:
     and returns map[string]string of the regex groups.
   - RegexDict: a parser that searches a string for values in a dictionary format,
     and returns a map[string]string of the keys and their associated value.
+  - OutputFixing: a parser that wraps another parser and, when it fails, asks an LLM
+    to fix the output using the format instructions and the parse error.
+  - Retry: a parser that wraps another parser and, when it fails, asks an LLM to
+    answer the original prompt again, given the failed output and the parse error.
+
+The package also provides ExtractJSON and ParseJSON, which leniently extract JSON
+from the output of an LLM, and PartialJSON, which parses JSON incrementally from
+streamed output chunks.
 */
 package outputparser
@@ -0,0 +1,394 @@
+package outputparser
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// ExtractJSON returns the first JSON object or array found in the output of an
+// LLM. The JSON can be in a markdown code block or bare, and surrounded by any
+// other text. Trailing commas are removed, and objects and arrays left open at
+// the end of truncated output are closed. If no JSON is found, or if the
+// output is cut in the middle of a key or value, a ParseError is returned.
+func ExtractJSON(text string) (string, error) {
+	return extractJSON(text, false)
+}
+
+// extractJSON implements ExtractJSON. If partial is true, output cut in the
+// middle of a key or value is completed too, so that the result is valid JSON:
+// unterminated strings are closed and incomplete keys and values are dropped.
+func extractJSON(text string, partial bool) (string, error) {
+	truncated := false
+	for _, candidate := range jsonCandidates(text) {
+		for i := 0; i < len(candidate); i++ {
+			if candidate[i] != '{' && candidate[i] != '[' {
+				continue
+			}
+			repaired, end := repairJSON(candidate[i:])
+			if !json.Valid([]byte(repaired)) {
+				continue
+			}
+			if end != jsonTruncated || partial {
+				return repaired, nil
+			}
+			truncated = true
+		}
+	}
+	if truncated {
+		return "", ParseError{Text: text, Reason: "incomplete JSON value in output"}
+	}
+	return "", ParseError{Text: text, Reason: "no JSON object or array found in output"}
+}
+
+// ParseJSON extracts JSON from the output of an LLM with ExtractJSON and
+// unmarshals it into target.
+func ParseJSON(text string, target any) error {
+	jsonString, err := ExtractJSON(text)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal([]byte(jsonString), target); err != nil {
+		return ParseError{Text: text, Reason: err.Error()}
+	}
+	return nil
+}
+
+// PartialJSON incrementally parses JSON from the chunks of a streamed LLM
+// output. The first JSON object or array of the output, bare or in a markdown
+// code block, is parsed as its chunks are received and, while truncated,
+// completed like with ExtractJSON, so that progressively more complete values
+// are available before the output is finished. Only the text added by a chunk
+// is parsed.
+type PartialJSON struct {
+	text  strings.Builder
+	value any
+
+	// repairer parses the value starting at offset start of the text, which
+	// was parsed up to offset parsed.
+	repairer *jsonRepairer
+	start    int
+	parsed   int
+	// completed is the completion of the value last decoded.
+	completed string
+}
+
+// NewPartialJSON creates a new, empty, partial JSON parser.
+func NewPartialJSON() *PartialJSON {
+	return &PartialJSON{}
+}
+
+// Add adds a chunk of output to the parser and returns the value parsed from
+// the output received so far. The returned boolean reports whether the value
+// changed since the previous chunk. The value is nil until the start of a JSON
+// object or array is received, and does not change once it is complete.
+func (p *PartialJSON) Add(chunk string) (any, bool) {
+	p.text.WriteString(chunk)
+	text := p.text.String()
+
+	for {
+		if p.repairer == nil {
+			i := strings.IndexAny(text[p.parsed:], "{[")
+			if i == -1 {
+				p.parsed = len(text)
+				return p.value, false
+			}
+			p.repairer = newJSONRepairer()
+			p.start = p.parsed + i
+			p.parsed = p.start
+		}
+		if p.repairer.done {
+			return p.value, false
+		}
+		p.parsed += p.repairer.write(text[p.parsed:])
+
+		if !p.repairer.invalid {
+			completed, _ := p.repairer.completion()
+			if completed == p.completed {
+				return p.value, false
+			}
+			var value any
+			if err := json.Unmarshal([]byte(completed), &value); err == nil {
+				p.completed = completed
+				return p.setValue(value)
+			}
+		}
+		// Not JSON, such as "[answer]" in text: look for JSON after its start.
+		p.repairer = nil
+		p.parsed = p.start + 1
+	}
+}
+
+// setValue sets the value of the parser and reports whether it changed.
+func (p *PartialJSON) setValue(value any) (any, bool) {
+	if reflect.DeepEqual(value, p.value) {
+		return p.value, false
+	}
+	p.value = value
+	return value, true
+}
+
+// Value returns the value parsed from the output received so far.
+func (p *PartialJSON) Value() any {
+	return p.value
+}
+
+// Text returns the output received so far.
+func (p *PartialJSON) Text() string {
+	return p.text.String()
+}
+
+// StreamPartialJSON returns a streaming function, to be used with
+// llms.WithStreamingFunc or chains.WithStreamingFunc, that parses the chunks it
+// receives with a PartialJSON and calls fn every time the parsed value changes.
+func StreamPartialJSON(fn func(ctx context.Context, value any) error) func(ctx context.Context, chunk []byte) error {
+	p := NewPartialJSON()
+	return func(ctx context.Context, chunk []byte) error {
+		value, changed := p.Add(string(chunk))
+		if !changed {
+			return nil
+		}
+		return fn(ctx, value)
+	}
+}
+
+// jsonCandidates returns the texts to search for JSON in: the contents of the
+// markdown code blocks of text, followed by the text itself.
+func jsonCandidates(text string) []string {
+	const fence = "```"
+
+	candidates := make([]string, 0)
+	rest := text
+	for {
+		_, after, ok := strings.Cut(rest, fence)
+		if !ok {
+			break
+		}
+		// Skip the language of the code block, if any.
+		if newline := strings.IndexByte(after, '\n'); newline != -1 &&
+			!strings.ContainsAny(after[:newline], "{[") {
+			after = after[newline+1:]
+		}
+		block, remaining, closed := strings.Cut(after, fence)
+		candidates = append(candidates, block)
+		if !closed {
+			break
+		}
+		rest = remaining
+	}
+	return append(candidates, text)
+}
+
+// jsonEnd is how the text given to repairJSON ends.
+type jsonEnd int
+
+const (
+	// jsonComplete is a complete value.
+	jsonComplete jsonEnd = iota
+	// jsonUnclosed is a value truncated between two of its elements, whose
+	// open objects and arrays only need to be closed.
+	jsonUnclosed
+	// jsonTruncated is a value truncated in the middle of a key or value.
+	jsonTruncated
+)
+
+// jsonFrame is an object or array that is open while repairing JSON.
+type jsonFrame struct {
+	closing   byte
+	expectKey bool
+}
+
+// jsonRepairer repairs a JSON value written to it in one or more parts.
+type jsonRepairer struct {
+	out   []byte
+	stack []jsonFrame
+
+	inString  bool
+	isKey     bool
+	escaped   bool
+	primitive int
+
+	// done reports whether the value is complete, or was ended by the closing
+	// fence of a markdown code block, and invalid whether it is not JSON.
+	done    bool
+	invalid bool
+
+	// safeLen and safeStack record the last point at which the output can be
+	// cut and completed by closing the open objects and arrays.
+	safeLen   int
+	safeStack []jsonFrame
+}
+
+func newJSONRepairer() *jsonRepairer {
+	return &jsonRepairer{primitive: -1}
+}
+
+// repairJSON repairs the JSON value starting at the beginning of text, which
+// must be '{' or '['. Text after the end of the value is ignored.
+func repairJSON(text string) (string, jsonEnd) {
+	r := newJSONRepairer()
+	r.write(text)
+	return r.completion()
+}
+
+// write parses text as the continuation of the value and returns the number of
+// bytes parsed, which is less than the length of text if the value ended.
+func (r *jsonRepairer) write(text string) int { //nolint:cyclop
+	for i := 0; i < len(text); i++ {
+		if r.done || r.invalid {
+			return i
+		}
+		c := text[i]
+		if r.inString {
+			r.addStringByte(c)
+			continue
+		}
+		if r.primitive != -1 && isJSONDelimiter(c) {
+			r.endPrimitive()
+		}
+
+		switch c {
+		case '"':
+			r.inString = true
+			r.isKey = r.inObjectKey()
+			r.out = append(r.out, c)
+		case '{', '[':
+			closing := byte('}')
+			if c == '[' {
+				closing = ']'
+			}
+			r.stack = append(r.stack, jsonFrame{closing: closing, expectKey: c == '{'})
+			r.out = append(r.out, c)
+			r.markSafe()
+		case '}', ']':
+			if len(r.stack) == 0 {
+				r.invalid = true
+				continue
+			}
+			// Drop trailing commas.
+			r.out = bytes.TrimRight(r.out, " \t\r\n")
+			r.out = bytes.TrimSuffix(r.out, []byte(","))
+			r.out = append(r.out, r.stack[len(r.stack)-1].closing)
+			r.stack = r.stack[:len(r.stack)-1]
+			r.markSafe()
+			r.done = len(r.stack) == 0
+		case ',':
+			r.out = append(r.out, c)
+			if top := r.top(); top != nil && top.closing == '}' {
+				top.expectKey = true
+			}
+		case ':':
+			r.out = append(r.out, c)
+			if top := r.top(); top != nil {
+				top.expectKey = false
+			}
+		case ' ', '\t', '\r', '\n':
+			r.out = append(r.out, c)
+		case '`':
+			// The closing fence of a markdown code block ends the output.
+			r.done = true
+		default:
+			if r.primitive == -1 {
+				r.primitive = len(r.out)
+			}
+			r.out = append(r.out, c)
+		}
+	}
+	return len(text)
+}
+
+// completion returns the value written so far, completed if it is truncated,
+// and how it ends.
+func (r *jsonRepairer) completion() (string, jsonEnd) {
+	if r.done && len(r.stack) == 0 {
+		return string(r.out), jsonComplete
+	}
+
+	safeLen, safeStack := r.safeLen, r.safeStack
+	out := r.out
+	end := jsonUnclosed
+	if r.inString || r.primitive != -1 || len(bytes.Trim(out[safeLen:], " \t\r\n,")) > 0 {
+		end = jsonTruncated
+	}
+
+	// Complete the value being written if possible.
+	switch {
+	case r.inString && !r.isKey:
+		out = append([]byte{}, out...)
+		if r.escaped {
+			out = out[:len(out)-1]
+		}
+		// Drop an incomplete unicode escape.
+		if i := bytes.LastIndex(out, []byte(`\u`)); i != -1 && len(out)-i < len(`\u0000`) {
+			out = out[:i]
+		}
+		out = append(out, '"')
+		safeLen, safeStack = len(out), r.stack
+	case r.primitive != -1 && json.Valid(bytes.TrimSpace(out[r.primitive:])):
+		safeLen, safeStack = len(out), r.stack
+	}
+
+	completed := bytes.TrimRight(out[:safeLen:safeLen], " \t\r\n")
+	completed = bytes.TrimSuffix(completed, []byte(","))
+	completed = append([]byte{}, completed...)
+	for i := len(safeStack) - 1; i >= 0; i-- {
+		completed = append(completed, safeStack[i].closing)
+	}
+	return string(completed), end
+}
+
+func (r *jsonRepairer) addStringByte(c byte) {
+	switch {
+	case r.escaped:
+		r.escaped = false
+	case c == '\\':
+		r.escaped = true
+	case c == '"':
+		r.inString = false
+		r.out = append(r.out, c)
+		if !r.isKey {
+			r.markSafe()
+		}
+		return
+	case c == '\n':
+		// Raw newlines are not valid in JSON strings.
+		r.out = append(r.out, '\\', 'n')
+		return
+	}
+	r.out = append(r.out, c)
+}
+
+// endPrimitive ends the number, boolean or null being written, marking the
+// output as safe if it is valid and the value as invalid otherwise.
+func (r *jsonRepairer) endPrimitive() {
+	token := bytes.TrimSpace(r.out[r.primitive:])
+	r.primitive = -1
+	if !json.Valid(token) {
+		r.invalid = true
+		return
+	}
+	r.markSafe()
+}
+
+func (r *jsonRepairer) inObjectKey() bool {
+	top := r.top()
+	return top != nil && top.closing == '}' && top.expectKey
+}
+
+func (r *jsonRepairer) top() *jsonFrame {
+	if len(r.stack) == 0 {
+		return nil
+	}
+	return &r.stack[len(r.stack)-1]
+}
+
+func (r *jsonRepairer) markSafe() {
+	r.safeLen = len(r.out)
+	r.safeStack = append(r.safeStack[:0], r.stack...)
+}
+
+func isJSONDelimiter(c byte) bool {
+	return strings.IndexByte(",:{}[]\" \t\r\n`", c) != -1
+}
@@ -0,0 +1,128 @@
+package outputparser
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExtractJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		"bare":             {input: `{"a": 1}`, expected: `{"a": 1}`},
+		"fenced":           {input: "```json\n{\"a\": 1}\n```", expected: `{"a": 1}`},
+		"fenced no lang":   {input: "```\n[1, 2]\n```", expected: `[1, 2]`},
+		"leading prose":    {input: "Sure! Here is the [answer]: {\"a\": \"b\"} Hope it helps.", expected: `{"a": "b"}`},
+		"trailing commas":  {input: `{"a": [1, 2,], "b": 3,}`, expected: `{"a": [1, 2], "b": 3}`},
+		"braces in string": {input: `{"a": "}{"}`, expected: `{"a": "}{"}`},
+		"raw newline":      {input: "{\"a\": \"b\nc\"}", expected: `{"a": "b\nc"}`},
+		"no json":          {input: "I don't know", wantErr: true},
+		"empty":            {input: "", wantErr: true},
+		"unclosed object":  {input: `{"a": 1, "b": "x"`, expected: `{"a": 1, "b": "x"}`},
+		"unclosed array":   {input: "```json\n[\"a\", {\"b\": [1, 2]},\n", expected: `["a", {"b": [1, 2]}]`},
+		"truncated":        {input: `{"a": 1, "b": "hel`, wantErr: true},
+		"truncated number": {input: `{"a": 1, "b": 2`, wantErr: true},
+		"truncated key":    {input: `{"a": 1, "b`, wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			output, err := ExtractJSON(tc.input)
+			if tc.wantErr {
+				require.ErrorAs(t, err, &ParseError{})
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, output)
+		})
+	}
+}
+
+func TestExtractPartialJSON(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input    string
+		expected string
+	}{
+		"truncated string": {input: `{"a": 1, "b": "hel`, expected: `{"a": 1, "b": "hel"}`},
+		"truncated key":    {input: `{"a": 1, "b`, expected: `{"a": 1}`},
+		"truncated colon":  {input: `{"a": 1, "b":`, expected: `{"a": 1}`},
+		"truncated value":  {input: `{"a": [true, fal`, expected: `{"a": [true]}`},
+		"truncated number": {input: `{"a": [1, 23`, expected: `{"a": [1, 23]}`},
+		"truncated fence":  {input: "```json\n{\"a\": {\"b\": \"c\\", expected: `{"a": {"b": "c"}}`},
+		"complete":         {input: `{"a": 1}`, expected: `{"a": 1}`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			output, err := extractJSON(tc.input, true)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, output)
+		})
+	}
+}
+
+func TestPartialJSON(t *testing.T) {
+	t.Parallel()
+
+	chunks := []string{"Here you go:\n```json\n", `{"name": "Ad`, `a", "langs": [`, `"Go", "Rust"`, "]}\n```"}
+	expected := []any{
+		nil,
+		map[string]any{"name": "Ad"},
+		map[string]any{"name": "Ada", "langs": []any{}},
+		map[string]any{"name": "Ada", "langs": []any{"Go", "Rust"}},
+		map[string]any{"name": "Ada", "langs": []any{"Go", "Rust"}},
+	}
+
+	var streamed []any
+	streamingFunc := StreamPartialJSON(func(_ context.Context, value any) error {
+		streamed = append(streamed, value)
+		return nil
+	})
+
+	p := NewPartialJSON()
+	for i, chunk := range chunks {
+		value, _ := p.Add(chunk)
+		assert.Equal(t, expected[i], value)
+		require.NoError(t, streamingFunc(context.Background(), []byte(chunk)))
+	}
+	assert.Equal(t, expected[1:4], streamed)
+}
+
+func TestPartialJSONIncremental(t *testing.T) {
+	t.Parallel()
+
+	text := "Sure! Here is the [answer]:\n```json\n{\"a\": [1, 2.5, true], \"b\": {\"c\": \"d\\\"e\\u00e9\"}}\n```\nDone."
+	p := NewPartialJSON()
+	start := strings.Index(text, "{")
+	for i := range text {
+		value, _ := p.Add(text[i : i+1])
+		if i < start {
+			continue
+		}
+		// Parsing byte by byte gives the same values as parsing the whole text
+		// received so far.
+		jsonString, err := extractJSON(text[start:i+1], true)
+		require.NoError(t, err)
+		var expected any
+		require.NoError(t, json.Unmarshal([]byte(jsonString), &expected))
+		assert.Equal(t, expected, value, text[:i+1])
+	}
+	assert.Equal(t, map[string]any{
+		"a": []any{1.0, 2.5, true},
+		"b": map[string]any{"c": "d\"eé"},
+	}, p.Value())
+	assert.Equal(t, text, p.Text())
+}
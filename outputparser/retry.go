@@ -0,0 +1,233 @@
+package outputparser
+
+import (
+	"context"
+
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const (
+	// _defaultMaxRetries is the default number of times the model is asked to
+	// correct an output.
+	_defaultMaxRetries = 1
+
+	_outputFixingTemplate = `Instructions:
+--------------
+{{.instructions}}
+--------------
+Completion:
+--------------
+{{.completion}}
+--------------
+
+Above, the Completion did not satisfy the constraints given in the Instructions.
+Error:
+--------------
+{{.error}}
+--------------
+
+Please try again. Please only respond with an answer that satisfies the constraints laid out in the Instructions:`
+
+	_retryTemplate = `Prompt:
+{{.prompt}}
+Completion:
+{{.completion}}
+
+Above, the Completion did not satisfy the constraints given in the Prompt.
+Details: {{.error}}
+Please try again:`
+)
+
+// ContextParser is implemented by output parsers that call a model while
+// parsing, and can therefore use the context and call options of the caller.
+type ContextParser[T any] interface {
+	// ParseWithPromptContext parses the output of an LLM call with the prompt
+	// used, calling the model with the given options if needed.
+	ParseWithPromptContext(
+		ctx context.Context, text string, prompt llms.PromptValue, options ...llms.CallOption,
+	) (T, error)
+}
+
+// OutputFixing is an output parser that wraps another parser. When the wrapped
+// parser fails, the model is sent the format instructions of the parser, the
+// output that failed and the parse error, and asked to fix the output. This is
+// repeated up to MaxRetries times.
+type OutputFixing[T any] struct {
+	Parser     schema.OutputParser[T]
+	LLM        llms.Model
+	MaxRetries int
+	Prompt     prompts.PromptTemplate
+}
+
+// NewOutputFixing creates a new output fixing parser wrapping parser. The model
+// is asked to fix the output up to maxRetries times, or once if maxRetries is
+// not positive.
+func NewOutputFixing[T any](parser schema.OutputParser[T], llm llms.Model, maxRetries int) OutputFixing[T] {
+	if maxRetries <= 0 {
+		maxRetries = _defaultMaxRetries
+	}
+	return OutputFixing[T]{
+		Parser:     parser,
+		LLM:        llm,
+		MaxRetries: maxRetries,
+		Prompt: prompts.NewPromptTemplate(
+			_outputFixingTemplate, []string{"instructions", "completion", "error"},
+		),
+	}
+}
+
+// Statically assert that OutputFixing implement the OutputParser interface.
+var (
+	_ schema.OutputParser[any] = OutputFixing[any]{}
+	_ ContextParser[any]       = OutputFixing[any]{}
+)
+
+// GetFormatInstructions returns the format instructions of the wrapped parser.
+func (p OutputFixing[T]) GetFormatInstructions() string {
+	return p.Parser.GetFormatInstructions()
+}
+
+// Parse parses the output of an LLM call, asking the model to fix it if needed.
+func (p OutputFixing[T]) Parse(text string) (T, error) {
+	return p.ParseWithPromptContext(context.Background(), text, nil)
+}
+
+// ParseWithPrompt parses the output of an LLM call with the prompt used, asking
+// the model to fix it if needed.
+func (p OutputFixing[T]) ParseWithPrompt(text string, prompt llms.PromptValue) (T, error) {
+	return p.ParseWithPromptContext(context.Background(), text, prompt)
+}
+
+// ParseWithPromptContext parses the output of an LLM call with the prompt used,
+// asking the model to fix it with the given call options if needed.
+func (p OutputFixing[T]) ParseWithPromptContext(
+	ctx context.Context, text string, prompt llms.PromptValue, options ...llms.CallOption,
+) (T, error) {
+	return parseWithRetries(ctx, p.Parser, p.LLM, p.MaxRetries, text, prompt, options,
+		func(completion string, err error) (string, error) {
+			return p.Prompt.Format(map[string]any{
+				"instructions": p.Parser.GetFormatInstructions(),
+				"completion":   completion,
+				"error":        err.Error(),
+			})
+		},
+	)
+}
+
+// Type returns the string type key uniquely identifying this class of parser.
+func (p OutputFixing[T]) Type() string {
+	return "output_fixing_parser"
+}
+
+// Retry is an output parser that wraps another parser. When the wrapped parser
+// fails, the model is sent the original prompt, the output that failed and the
+// parse error, and asked to try again. This is repeated up to MaxRetries times.
+// As the original prompt is needed, Parse does not retry; use ParseWithPrompt.
+type Retry[T any] struct {
+	Parser     schema.OutputParser[T]
+	LLM        llms.Model
+	MaxRetries int
+	Prompt     prompts.PromptTemplate
+}
+
+// NewRetry creates a new retry parser wrapping parser. The model is asked to
+// try again up to maxRetries times, or once if maxRetries is not positive.
+func NewRetry[T any](parser schema.OutputParser[T], llm llms.Model, maxRetries int) Retry[T] {
+	if maxRetries <= 0 {
+		maxRetries = _defaultMaxRetries
+	}
+	return Retry[T]{
+		Parser:     parser,
+		LLM:        llm,
+		MaxRetries: maxRetries,
+		Prompt: prompts.NewPromptTemplate(
+			_retryTemplate, []string{"prompt", "completion", "error"},
+		),
+	}
+}
+
+// Statically assert that Retry implement the OutputParser interface.
+var (
+	_ schema.OutputParser[any] = Retry[any]{}
+	_ ContextParser[any]       = Retry[any]{}
+)
+
+// GetFormatInstructions returns the format instructions of the wrapped parser.
+func (p Retry[T]) GetFormatInstructions() string {
+	return p.Parser.GetFormatInstructions()
+}
+
+// Parse parses the output of an LLM call with the wrapped parser. It does not
+// retry, as the prompt is unknown.
+func (p Retry[T]) Parse(text string) (T, error) {
+	return p.Parser.Parse(text)
+}
+
+// ParseWithPrompt parses the output of an LLM call with the prompt used, asking
+// the model to try again if needed.
+func (p Retry[T]) ParseWithPrompt(text string, prompt llms.PromptValue) (T, error) {
+	return p.ParseWithPromptContext(context.Background(), text, prompt)
+}
+
+// ParseWithPromptContext parses the output of an LLM call with the prompt used,
+// asking the model to try again with the given call options if needed.
+func (p Retry[T]) ParseWithPromptContext(
+	ctx context.Context, text string, prompt llms.PromptValue, options ...llms.CallOption,
+) (T, error) {
+	if prompt == nil {
+		return p.Parser.Parse(text)
+	}
+	return parseWithRetries(ctx, p.Parser, p.LLM, p.MaxRetries, text, prompt, options,
+		func(completion string, err error) (string, error) {
+			return p.Prompt.Format(map[string]any{
+				"prompt":     prompt.String(),
+				"completion": completion,
+				"error":      err.Error(),
+			})
+		},
+	)
+}
+
+// Type returns the string type key uniquely identifying this class of parser.
+func (p Retry[T]) Type() string {
+	return "retry_parser"
+}
+
+// parseWithRetries parses text with parser and, while parsing fails, asks the
+// model for a new completion, with the given call options, using the prompt
+// built by retryPrompt. The error of the last attempt is returned if every
+// attempt fails.
+func parseWithRetries[T any](
+	ctx context.Context,
+	parser schema.OutputParser[T],
+	llm llms.Model,
+	maxRetries int,
+	text string,
+	prompt llms.PromptValue,
+	options []llms.CallOption,
+	retryPrompt func(completion string, err error) (string, error),
+) (T, error) {
+	result, err := parse(parser, text, prompt)
+	for i := 0; err != nil && i < maxRetries; i++ {
+		var p string
+		p, err = retryPrompt(text, err)
+		if err != nil {
+			return result, err
+		}
+		text, err = llms.GenerateFromSinglePrompt(ctx, llm, p, options...)
+		if err != nil {
+			return result, err
+		}
+		result, err = parse(parser, text, prompt)
+	}
+	return result, err
+}
+
+func parse[T any](parser schema.OutputParser[T], text string, prompt llms.PromptValue) (T, error) {
+	if prompt == nil {
+		return parser.Parse(text)
+	}
+	return parser.ParseWithPrompt(text, prompt)
+}
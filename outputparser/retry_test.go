@@ -0,0 +1,84 @@
+package outputparser
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/prompts"
+)
+
+// recordingLLM returns canned responses in order and records the prompts.
+type recordingLLM struct {
+	responses []string
+	prompts   []string
+}
+
+func (l *recordingLLM) GenerateContent(
+	_ context.Context, messages []llms.MessageContent, _ ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	l.prompts = append(l.prompts, messages[0].Parts[0].(llms.TextContent).Text)
+	response := l.responses[0]
+	l.responses = l.responses[1:]
+	return &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: response}}}, nil
+}
+
+func (l *recordingLLM) Call(context.Context, string, ...llms.CallOption) (string, error) {
+	panic("not implemented")
+}
+
+func TestOutputFixing(t *testing.T) {
+	t.Parallel()
+
+	llm := &recordingLLM{responses: []string{"maybe", "yes"}}
+	parser := NewOutputFixing[any](NewBooleanParser(), llm, 2)
+
+	result, err := parser.Parse("perhaps")
+	require.NoError(t, err)
+	assert.Equal(t, true, result)
+
+	require.Len(t, llm.prompts, 2)
+	assert.Contains(t, llm.prompts[0], NewBooleanParser().GetFormatInstructions())
+	assert.Contains(t, llm.prompts[0], "perhaps")
+	assert.Contains(t, llm.prompts[1], "MAYBE")
+}
+
+func TestOutputFixingMaxRetries(t *testing.T) {
+	t.Parallel()
+
+	llm := &recordingLLM{responses: []string{"maybe"}}
+	parser := NewOutputFixing[any](NewBooleanParser(), llm, 1)
+
+	_, err := parser.Parse("perhaps")
+	var parseErr ParseError
+	require.ErrorAs(t, err, &parseErr)
+	assert.Equal(t, "MAYBE", parseErr.Text)
+	assert.Len(t, llm.prompts, 1)
+}
+
+func TestRetry(t *testing.T) {
+	t.Parallel()
+
+	llm := &recordingLLM{responses: []string{"no"}}
+	parser := NewRetry[any](NewBooleanParser(), llm, 3)
+
+	promptValue, err := prompts.NewPromptTemplate(
+		"Is {{.city}} the capital of France?", []string{"city"},
+	).FormatPrompt(map[string]any{"city": "Lyon"})
+	require.NoError(t, err)
+
+	result, err := parser.ParseWithPrompt("I am not sure", promptValue)
+	require.NoError(t, err)
+	assert.Equal(t, false, result)
+
+	require.Len(t, llm.prompts, 1)
+	assert.Contains(t, llm.prompts[0], "Is Lyon the capital of France?")
+	assert.Contains(t, llm.prompts[0], "I am not sure")
+
+	// Without a prompt, the retry parser does not call the model.
+	_, err = parser.Parse("I am not sure")
+	require.Error(t, err)
+	assert.Len(t, llm.prompts, 1)
+}
@@ -1,9 +1,7 @@
 package outputparser
 
 import (
-	"encoding/json"
 	"fmt"
-	"strings"
 
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/schema"
@@ -61,21 +59,8 @@ var _ schema.OutputParser[any] = Structured{}
 // contain every filed specified in the response schemas, the function will return
 // an error.
 func (p Structured) parse(text string) (map[string]string, error) {
-	// Remove the ```json that should be at the start of the text, and the ```
-	// that should be at the end of the text.
-	_, withoutJSONStart, ok := strings.Cut(text, "```json")
-	if !ok {
-		return nil, ParseError{Text: text, Reason: "no ```json at start of output"}
-	}
-
-	jsonString, _, ok := strings.Cut(withoutJSONStart, "```")
-	if !ok {
-		return nil, ParseError{Text: text, Reason: "no ``` at end of output"}
-	}
-
 	var parsed map[string]string
-	err := json.Unmarshal([]byte(jsonString), &parsed)
-	if err != nil {
+	if err := ParseJSON(text, &parsed); err != nil {
 		return nil, err
 	}
 
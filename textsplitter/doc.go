@@ -9,6 +9,7 @@ The main components of this package are:
 - TextSplitter interface: a common interface for splitting texts into smaller chunks.
 - RecursiveCharacter: a text splitter that recursively splits texts by different characters (separators)
 combined with chunk size and overlap settings.
+- Semantic: a text splitter that uses an embedder to split texts where the topic of adjacent sentences changes.
 - Helper functions: utility functions for creating documents out of split texts and rejoining them if necessary.
 
 Using the TextSplitter interface, developers can implement custom
@@ -18,6 +18,10 @@ type Options struct {
 	ReferenceLinks       bool
 	KeepHeadingHierarchy bool // Persist hierarchy of markdown headers in each chunk
 	JoinTableRows        bool
+
+	BreakpointThresholdType   BreakpointThresholdType
+	BreakpointThresholdAmount float64
+	BufferSize                int
 }
 
 // DefaultOptions returns the default options for all text splitter.
@@ -35,6 +39,8 @@ func DefaultOptions() Options {
 		DisallowedSpecial: []string{"all"},
 
 		KeepHeadingHierarchy: false,
+
+		BufferSize: _defaultSemanticBufferSize,
 	}
 }
 
@@ -157,3 +163,24 @@ func WithJoinTableRows(join bool) Option {
 		o.JoinTableRows = join
 	}
 }
+
+// WithBreakpointThreshold sets how a semantic splitter decides where to split.
+// The amount is interpreted according to the threshold type: a percentile for
+// BreakpointPercentile, a number of standard deviations for
+// BreakpointStandardDeviation and a multiple of the interquartile range for
+// BreakpointInterquartile. An amount of zero selects the default for the type.
+func WithBreakpointThreshold(thresholdType BreakpointThresholdType, amount float64) Option {
+	return func(o *Options) {
+		o.BreakpointThresholdType = thresholdType
+		o.BreakpointThresholdAmount = amount
+	}
+}
+
+// WithBufferSize sets the number of neighbouring sentences, on each side, that a
+// semantic splitter embeds together with every sentence. Larger values smooth
+// out the distances between adjacent sentences.
+func WithBufferSize(bufferSize int) Option {
+	return func(o *Options) {
+		o.BufferSize = bufferSize
+	}
+}
@@ -0,0 +1,260 @@
+package textsplitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/tmc/langchaingo/embeddings"
+)
+
+const (
+	_defaultSemanticBufferSize               = 1
+	_defaultPercentileThreshold              = 95
+	_defaultStandardDeviationThreshold       = 3
+	_defaultInterquartileThreshold           = 1.5
+	_semanticSentenceSeparator               = " "
+	_percentileMax                           = 100
+	_interquartileLower, _interquartileUpper = 25, 75
+)
+
+// ErrMissingEmbedder is returned when a semantic splitter is used without an
+// embedder.
+var ErrMissingEmbedder = errors.New("semantic splitter requires an embedder")
+
+// BreakpointThresholdType is the method used by the semantic splitter to decide
+// whether the distance between two adjacent sentences is large enough to start
+// a new chunk.
+type BreakpointThresholdType string
+
+const (
+	// BreakpointPercentile splits where the distance is greater than the given
+	// percentile of all distances.
+	BreakpointPercentile BreakpointThresholdType = "percentile"
+	// BreakpointStandardDeviation splits where the distance is greater than the
+	// mean plus the given number of standard deviations.
+	BreakpointStandardDeviation BreakpointThresholdType = "standard_deviation"
+	// BreakpointInterquartile splits where the distance is greater than the mean
+	// plus the given multiple of the interquartile range.
+	BreakpointInterquartile BreakpointThresholdType = "interquartile"
+)
+
+// Semantic is a text splitter that splits texts at points where the meaning of
+// adjacent sentences changes. Each sentence is embedded together with its
+// neighbours, and a new chunk is started wherever the cosine distance between
+// two adjacent sentences exceeds the configured threshold. Chunks that are
+// still longer than ChunkSize, as measured by LenFunc, are merged back down
+// sentence by sentence.
+type Semantic struct {
+	Embedder                  embeddings.Embedder
+	BreakpointThresholdType   BreakpointThresholdType
+	BreakpointThresholdAmount float64
+	BufferSize                int
+	ChunkSize                 int
+	LenFunc                   func(string) int
+}
+
+// NewSemantic creates a new semantic splitter using the given embedder. By
+// default, breakpoints are placed where the distance between sentences is
+// above the 95th percentile, and each sentence is embedded together with one
+// sentence on either side.
+func NewSemantic(embedder embeddings.Embedder, opts ...Option) Semantic {
+	options := DefaultOptions()
+	for _, o := range opts {
+		o(&options)
+	}
+
+	thresholdType := options.BreakpointThresholdType
+	if thresholdType == "" {
+		thresholdType = BreakpointPercentile
+	}
+	thresholdAmount := options.BreakpointThresholdAmount
+	if thresholdAmount == 0 {
+		thresholdAmount = defaultBreakpointThresholdAmount(thresholdType)
+	}
+
+	return Semantic{
+		Embedder:                  embedder,
+		BreakpointThresholdType:   thresholdType,
+		BreakpointThresholdAmount: thresholdAmount,
+		BufferSize:                options.BufferSize,
+		ChunkSize:                 options.ChunkSize,
+		LenFunc:                   options.LenFunc,
+	}
+}
+
+// SplitText splits a text into multiple text.
+func (s Semantic) SplitText(text string) ([]string, error) {
+	return s.SplitTextWithContext(context.Background(), text)
+}
+
+// SplitTextWithContext splits a text into multiple text, using ctx for the
+// calls made to the embedder.
+func (s Semantic) SplitTextWithContext(ctx context.Context, text string) ([]string, error) {
+	if s.Embedder == nil {
+		return nil, ErrMissingEmbedder
+	}
+
+	sentences := splitSentences(text)
+	if len(sentences) <= 1 {
+		return s.limitChunkSize(sentences), nil
+	}
+
+	vectors, err := s.Embedder.EmbedDocuments(ctx, combineSentences(sentences, s.BufferSize))
+	if err != nil {
+		return nil, fmt.Errorf("embed sentences: %w", err)
+	}
+	if len(vectors) != len(sentences) {
+		return nil, fmt.Errorf("embed sentences: got %d vectors for %d sentences", len(vectors), len(sentences))
+	}
+
+	distances := make([]float64, len(vectors)-1)
+	for i := 0; i < len(vectors)-1; i++ {
+		distances[i] = 1 - cosineSimilarity(vectors[i], vectors[i+1])
+	}
+
+	threshold, err := s.threshold(distances)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make([]string, 0)
+	start := 0
+	for i, distance := range distances {
+		if distance > threshold {
+			chunks = append(chunks, s.limitChunkSize(sentences[start:i+1])...)
+			start = i + 1
+		}
+	}
+	chunks = append(chunks, s.limitChunkSize(sentences[start:])...)
+
+	return chunks, nil
+}
+
+// limitChunkSize joins the sentences of a semantic group, merging them into
+// several chunks when the group does not fit into ChunkSize.
+func (s Semantic) limitChunkSize(sentences []string) []string {
+	if len(sentences) == 0 {
+		return []string{}
+	}
+	joined := strings.Join(sentences, _semanticSentenceSeparator)
+	if s.ChunkSize <= 0 || s.LenFunc == nil || s.LenFunc(joined) <= s.ChunkSize {
+		return []string{joined}
+	}
+	return mergeSplits(sentences, _semanticSentenceSeparator, s.ChunkSize, 0, s.LenFunc)
+}
+
+func (s Semantic) threshold(distances []float64) (float64, error) {
+	switch s.BreakpointThresholdType {
+	case BreakpointPercentile, "":
+		return percentile(distances, s.BreakpointThresholdAmount), nil
+	case BreakpointStandardDeviation:
+		mean, std := meanAndStandardDeviation(distances)
+		return mean + s.BreakpointThresholdAmount*std, nil
+	case BreakpointInterquartile:
+		mean, _ := meanAndStandardDeviation(distances)
+		iqr := percentile(distances, _interquartileUpper) - percentile(distances, _interquartileLower)
+		return mean + s.BreakpointThresholdAmount*iqr, nil
+	default:
+		return 0, fmt.Errorf("unknown breakpoint threshold type %q", s.BreakpointThresholdType)
+	}
+}
+
+func defaultBreakpointThresholdAmount(t BreakpointThresholdType) float64 {
+	switch t {
+	case BreakpointStandardDeviation:
+		return _defaultStandardDeviationThreshold
+	case BreakpointInterquartile:
+		return _defaultInterquartileThreshold
+	default:
+		return _defaultPercentileThreshold
+	}
+}
+
+// splitSentences splits a text into sentences, cutting after '.', '?' and '!'
+// when they are followed by whitespace.
+func splitSentences(text string) []string {
+	sentences := make([]string, 0)
+	runes := []rune(text)
+	start := 0
+	for i := 0; i < len(runes)-1; i++ {
+		if !strings.ContainsRune(".?!", runes[i]) || !unicode.IsSpace(runes[i+1]) {
+			continue
+		}
+		if sentence := strings.TrimSpace(string(runes[start : i+1])); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+		start = i + 1
+	}
+	if sentence := strings.TrimSpace(string(runes[start:])); sentence != "" {
+		sentences = append(sentences, sentence)
+	}
+	return sentences
+}
+
+// combineSentences returns, for each sentence, the sentence joined with
+// bufferSize sentences before and after it.
+func combineSentences(sentences []string, bufferSize int) []string {
+	combined := make([]string, len(sentences))
+	for i := range sentences {
+		start := max(0, i-bufferSize)
+		end := min(len(sentences), i+bufferSize+1)
+		combined[i] = strings.Join(sentences[start:end], _semanticSentenceSeparator)
+	}
+	return combined
+}
+
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// percentile returns the p-th percentile of values using linear interpolation
+// between the closest ranks.
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	rank := p / _percentileMax * float64(len(sorted)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	if lower < 0 {
+		return sorted[0]
+	}
+	if upper >= len(sorted) {
+		return sorted[len(sorted)-1]
+	}
+	return sorted[lower] + (rank-float64(lower))*(sorted[upper]-sorted[lower])
+}
+
+func meanAndStandardDeviation(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	mean := sum / float64(len(values))
+
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(values)))
+}
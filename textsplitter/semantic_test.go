@@ -0,0 +1,100 @@
+package textsplitter
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// topicEmbedder embeds texts by counting occurrences of a fixed set of topic
+// words, so that sentences about the same topic are close to each other.
+type topicEmbedder struct {
+	topics []string
+}
+
+func (e topicEmbedder) EmbedDocuments(ctx context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, 0, len(texts))
+	for _, text := range texts {
+		v, err := e.EmbedQuery(ctx, text)
+		if err != nil {
+			return nil, err
+		}
+		vectors = append(vectors, v)
+	}
+	return vectors, nil
+}
+
+func (e topicEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	v := make([]float32, len(e.topics))
+	for i, topic := range e.topics {
+		v[i] = float32(strings.Count(strings.ToLower(text), topic))
+	}
+	return v, nil
+}
+
+func TestSemanticSplitter(t *testing.T) {
+	t.Parallel()
+
+	embedder := topicEmbedder{topics: []string{"cat", "car"}}
+	text := "The cat sleeps. A cat purrs. Cats like fish. " +
+		"The car is fast. A car needs fuel. Cars have wheels."
+
+	type testCase struct {
+		name     string
+		opts     []Option
+		expected []string
+	}
+	testCases := []testCase{
+		{
+			name: "percentile",
+			opts: []Option{WithBufferSize(0)},
+			expected: []string{
+				"The cat sleeps. A cat purrs. Cats like fish.",
+				"The car is fast. A car needs fuel. Cars have wheels.",
+			},
+		},
+		{
+			name: "standard deviation",
+			opts: []Option{WithBufferSize(0), WithBreakpointThreshold(BreakpointStandardDeviation, 1)},
+			expected: []string{
+				"The cat sleeps. A cat purrs. Cats like fish.",
+				"The car is fast. A car needs fuel. Cars have wheels.",
+			},
+		},
+		{
+			name: "chunk size",
+			opts: []Option{WithBufferSize(0), WithChunkSize(30)},
+			expected: []string{
+				"The cat sleeps. A cat purrs.",
+				"Cats like fish.",
+				"The car is fast.",
+				"A car needs fuel.",
+				"Cars have wheels.",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		splitter := NewSemantic(embedder, tc.opts...)
+		chunks, err := splitter.SplitText(text)
+		require.NoError(t, err, tc.name)
+		assert.Equal(t, tc.expected, chunks, tc.name)
+	}
+}
+
+func TestSemanticSplitterWithoutEmbedder(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewSemantic(nil).SplitText("Hello. World.")
+	require.ErrorIs(t, err, ErrMissingEmbedder)
+}
+
+func TestSplitSentences(t *testing.T) {
+	t.Parallel()
+
+	sentences := splitSentences("Hi there! How are you?\nI'm fine. Version 1.2 is out.")
+	assert.Equal(t, []string{"Hi there!", "How are you?", "I'm fine.", "Version 1.2 is out."}, sentences)
+}
@@ -31,8 +31,13 @@ func (c Calculator) Name() string {
 // string. If the evaluator errors the error is given in the result to give the
 // agent the ability to retry.
 func (c Calculator) Call(ctx context.Context, input string) (string, error) {
-	if c.CallbacksHandler != nil {
-		c.CallbacksHandler.HandleToolStart(ctx, input)
+	handler := c.CallbacksHandler
+	if callbacks.ToolRunReported(ctx, c.Name(), handler) {
+		// The caller of the tool reports the call to the same handler.
+		handler = nil
+	}
+	if handler != nil {
+		handler.HandleToolStart(ctx, input)
 	}
 
 	v, err := starlark.Eval(&starlark.Thread{Name: "main"}, "input", input, math.Module.Members)
@@ -41,8 +46,8 @@ func (c Calculator) Call(ctx context.Context, input string) (string, error) {
 	}
 	result := v.String()
 
-	if c.CallbacksHandler != nil {
-		c.CallbacksHandler.HandleToolEnd(ctx, result)
+	if handler != nil {
+		handler.HandleToolEnd(ctx, result)
 	}
 
 	return result, nil
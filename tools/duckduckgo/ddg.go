@@ -43,8 +43,13 @@ func (t Tool) Description() string {
 
 // Call performs the search and return the result.
 func (t Tool) Call(ctx context.Context, input string) (string, error) {
-	if t.CallbacksHandler != nil {
-		t.CallbacksHandler.HandleToolStart(ctx, input)
+	handler := t.CallbacksHandler
+	if callbacks.ToolRunReported(ctx, t.Name(), handler) {
+		// The caller of the tool reports the call to the same handler.
+		handler = nil
+	}
+	if handler != nil {
+		handler.HandleToolStart(ctx, input)
 	}
 
 	result, err := t.client.Search(ctx, input)
@@ -52,14 +57,14 @@ func (t Tool) Call(ctx context.Context, input string) (string, error) {
 		if errors.Is(err, internal.ErrNoGoodResult) {
 			return "No good DuckDuckGo Search Results was found", nil
 		}
-		if t.CallbacksHandler != nil {
-			t.CallbacksHandler.HandleToolError(ctx, err)
+		if handler != nil {
+			handler.HandleToolError(ctx, err)
 		}
 		return "", err
 	}
 
-	if t.CallbacksHandler != nil {
-		t.CallbacksHandler.HandleToolEnd(ctx, result)
+	if handler != nil {
+		handler.HandleToolEnd(ctx, result)
 	}
 
 	return result, nil
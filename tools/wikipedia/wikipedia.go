@@ -59,20 +59,25 @@ func (t Tool) Description() string {
 // Call uses the wikipedia api to find the top search results for the input and returns
 // the first part of the documents combined.
 func (t Tool) Call(ctx context.Context, input string) (string, error) {
-	if t.CallbacksHandler != nil {
-		t.CallbacksHandler.HandleToolStart(ctx, input)
+	handler := t.CallbacksHandler
+	if callbacks.ToolRunReported(ctx, t.Name(), handler) {
+		// The caller of the tool reports the call to the same handler.
+		handler = nil
+	}
+	if handler != nil {
+		handler.HandleToolStart(ctx, input)
 	}
 
 	result, err := t.searchWiKi(ctx, input)
 	if err != nil {
-		if t.CallbacksHandler != nil {
-			t.CallbacksHandler.HandleToolError(ctx, err)
+		if handler != nil {
+			handler.HandleToolError(ctx, err)
 		}
 		return "", err
 	}
 
-	if t.CallbacksHandler != nil {
-		t.CallbacksHandler.HandleToolEnd(ctx, result)
+	if handler != nil {
+		handler.HandleToolEnd(ctx, result)
 	}
 
 	return result, nil
@@ -26,6 +26,7 @@ var _ schema.Retriever = Retriever{}
 
 // GetRelevantDocuments returns documents using the vector store.
 func (r Retriever) GetRelevantDocuments(ctx context.Context, query string) ([]schema.Document, error) {
+	ctx = callbacks.StartRun(ctx, callbacks.RunTypeRetriever, "vectorstore")
 	if r.CallbacksHandler != nil {
 		r.CallbacksHandler.HandleRetrieverStart(ctx, query)
 	}